@@ -0,0 +1,101 @@
+package restclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//BulkheadError is returned without calling the API when the bulkhead of the pool rejects the call
+type BulkheadError struct {
+	//True if the call waited in the queue until the QueueTimeout, false if the queue was full
+	QueueTimeout bool
+}
+
+func (e *BulkheadError) Error() string {
+	if e.QueueTimeout {
+		return "Bulkhead queue timeout exceeded"
+	}
+
+	return "Bulkhead queue is full"
+}
+
+//Limit of concurrent calls of a pool
+type bulkhead struct {
+	slots        chan struct{}
+	queued       int32
+	maxQueued    int32
+	queueTimeout time.Duration
+}
+
+func newBulkhead(maxConcurrent int, maxQueued int, queueTimeout time.Duration) *bulkhead {
+	b := new(bulkhead)
+	b.slots = make(chan struct{}, maxConcurrent)
+	b.maxQueued = int32(maxQueued)
+	b.queueTimeout = queueTimeout * time.Millisecond
+
+	return b
+}
+
+//Get a slot to do the call, waiting in the queue if all the slots are in use
+func (b *bulkhead) acquire(ctx context.Context) error {
+	if b.tryAcquire() {
+		return nil
+	}
+
+	//Reject the call if the queue is full
+	if atomic.AddInt32(&b.queued, 1) > b.maxQueued {
+		atomic.AddInt32(&b.queued, -1)
+		return &BulkheadError{}
+	}
+
+	defer atomic.AddInt32(&b.queued, -1)
+
+	var timeout <-chan time.Time
+
+	if b.queueTimeout > 0 {
+		timer := time.NewTimer(b.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return &BulkheadError{QueueTimeout: true}
+	}
+}
+
+//Get a slot only if there is one free, without waiting in the queue
+func (b *bulkhead) tryAcquire() bool {
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+//Free the slot of a finished call
+func (b *bulkhead) release() {
+	<-b.slots
+}
+
+//Body of a streamed response that frees the bulkhead slot when it is closed
+type bulkheadBody struct {
+	io.ReadCloser
+	bulkhead *bulkhead
+	once     sync.Once
+}
+
+func (body *bulkheadBody) Close() error {
+	error := body.ReadCloser.Close()
+	body.once.Do(body.bulkhead.release)
+
+	return error
+}
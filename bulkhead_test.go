@@ -0,0 +1,91 @@
+package restclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestBulkheadQueueFull(t *testing.T) {
+
+	//Create a client with 1 concurrent call and 1 queued call
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.MaxConcurrentRequests = 1
+	config.MaxQueuedRequests = 1
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do 3 slow calls at the same time
+	var wait sync.WaitGroup
+	errors := make(chan error, 3)
+
+	for i := 0; i < 3; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			_, err := client.Get("http://localhost:8080/slow")
+			errors <- err
+		}()
+	}
+
+	wait.Wait()
+	close(errors)
+
+	//Checks that only one call was rejected
+	rejected := 0
+	for err := range errors {
+		if bulkheadError, ok := err.(*BulkheadError); ok && !bulkheadError.QueueTimeout {
+			rejected++
+		} else if err != nil {
+			t.Fatal("We got an error", err)
+		}
+	}
+
+	if rejected != 1 {
+		t.Fatal("One call should be rejected", rejected)
+	}
+
+	//The end
+	fmt.Println("End TestBulkheadQueueFull")
+
+}
+
+func TestBulkheadQueueTimeout(t *testing.T) {
+
+	//Create a client with 1 concurrent call and a queue timeout of 10ms
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.MaxConcurrentRequests = 1
+	config.MaxQueuedRequests = 10
+	config.QueueTimeout = 10
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Keep the slot in use with a streamed call until its body is closed
+	response, err := client.GetStream("http://localhost:8080/echo")
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//The queued call exceeds the timeout
+	_, err = client.Get("http://localhost:8080/echo")
+	if bulkheadError, ok := err.(*BulkheadError); !ok || !bulkheadError.QueueTimeout {
+		t.Fatal("We should had got a queue timeout error", err)
+	}
+
+	//Closing the body frees the slot
+	ioutil.ReadAll(response.Body)
+	response.Body.Close()
+
+	if _, err = client.Get("http://localhost:8080/echo"); err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//The end
+	fmt.Println("End TestBulkheadQueueTimeout")
+
+}
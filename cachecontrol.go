@@ -0,0 +1,147 @@
+package restclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Directives of the Cache-Control header of a response
+type cacheControl struct {
+	maxAge         int
+	hasMaxAge      bool
+	noStore        bool
+	noCache        bool
+	mustRevalidate bool
+	//Seconds the expired response can be returned while it is refreshed
+	staleWhileRevalidate int
+}
+
+//Parse the Cache-Control header values, the unknown directives are ignored
+func parseCacheControl(values []string) cacheControl {
+	var directives cacheControl
+
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument := directive, ""
+
+			if index := strings.Index(directive, "="); index >= 0 {
+				name, argument = directive[:index], strings.Trim(strings.TrimSpace(directive[index+1:]), "\"")
+			}
+
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "max-age":
+				if seconds, error := strconv.Atoi(argument); error == nil {
+					directives.maxAge = seconds
+					directives.hasMaxAge = true
+				}
+			case "no-store":
+				directives.noStore = true
+			case "no-cache":
+				directives.noCache = true
+			case "must-revalidate":
+				directives.mustRevalidate = true
+			case "stale-while-revalidate":
+				if seconds, error := strconv.Atoi(argument); error == nil {
+					directives.staleWhileRevalidate = seconds
+				}
+			}
+
+			//private and public don't change how this cache (that is private) stores the response, and
+			//s-maxage and proxy-revalidate only apply to shared caches, so they are ignored
+		}
+	}
+
+	return directives
+}
+
+//CachePolicy is used to define how long the responses of a pool are cached, instead of or besides their headers
+type CachePolicy struct {
+	//Time the responses without max-age or Expires are fresh (in milliseconds)
+	DefaultTTL time.Duration
+	//Min and max time the responses are fresh, if they are not 0 (in milliseconds)
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	//Ignore the Cache-Control, Expires and Age headers, caching every response during the DefaultTTL
+	IgnoreServerHeaders bool
+}
+
+//Return how a response can be cached following the headers and the cache policy of the pool, if there is one:
+//if it can be stored, until when it is fresh and if it must be revalidated once it expires (so it can't be returned
+//as a staled response)
+func getPolicyFreshness(headers map[string][]string, policy *CachePolicy) (expires time.Time, store bool, mustRevalidate bool) {
+	now := time.Now()
+
+	if policy != nil && policy.IgnoreServerHeaders {
+		return now.Add(policy.DefaultTTL * time.Millisecond), policy.DefaultTTL > 0, false
+	}
+
+	directives := parseCacheControl(headers["Cache-Control"])
+	header := http.Header(headers)
+
+	if directives.noStore {
+		return now, false, false
+	}
+
+	var lifetime time.Duration
+	hasLifetime := true
+
+	if directives.hasMaxAge {
+		//The max-age directive has priority over the Expires header
+		lifetime = time.Duration(directives.maxAge) * time.Second
+
+	} else if expiresHeader := header.Get("Expires"); expiresHeader != "" {
+		//An invalid Expires header means that the response is already expired
+		if expiresDate, error := http.ParseTime(expiresHeader); error == nil {
+			date, error := http.ParseTime(header.Get("Date"))
+			if error != nil {
+				date = now
+			}
+
+			lifetime = expiresDate.Sub(date)
+		}
+	} else {
+		hasLifetime = false
+	}
+
+	//Remove the time the response spent in other caches
+	if age, error := strconv.Atoi(header.Get("Age")); error == nil && age > 0 {
+		lifetime -= time.Duration(age) * time.Second
+	}
+
+	hasValidator := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+
+	if policy != nil {
+		lifetime = policy.clamp(lifetime, hasLifetime)
+	}
+
+	//A no-cache response can be stored, but it must be revalidated before every use
+	if directives.noCache {
+		return now, hasValidator, true
+	}
+
+	if lifetime > 0 {
+		return now.Add(lifetime), true, directives.mustRevalidate
+	}
+
+	//Without freshness the response is only useful to be revalidated
+	return now, hasValidator, directives.mustRevalidate
+}
+
+//Apply the default, min and max times of the policy to the lifetime of a response
+func (policy *CachePolicy) clamp(lifetime time.Duration, hasLifetime bool) time.Duration {
+	if !hasLifetime && policy.DefaultTTL > 0 {
+		lifetime = policy.DefaultTTL * time.Millisecond
+	}
+
+	if policy.MinTTL > 0 && lifetime < policy.MinTTL*time.Millisecond {
+		lifetime = policy.MinTTL * time.Millisecond
+	}
+
+	if policy.MaxTTL > 0 && lifetime > policy.MaxTTL*time.Millisecond {
+		lifetime = policy.MaxTTL * time.Millisecond
+	}
+
+	return lifetime
+}
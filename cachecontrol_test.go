@@ -0,0 +1,195 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+
+	//Parse several directives with spaces, quotes and different case
+	directives := parseCacheControl([]string{"public, Max-Age=\"60\"", "must-revalidate,s-maxage=600"})
+
+	if !directives.hasMaxAge || directives.maxAge != 60 || !directives.mustRevalidate {
+		t.Fatal("The directives were not as expected", directives)
+	}
+
+	//An invalid max-age is ignored
+	directives = parseCacheControl([]string{"max-age=abc, no-store"})
+
+	if directives.hasMaxAge || !directives.noStore {
+		t.Fatal("The directives were not as expected", directives)
+	}
+
+	//The stale-while-revalidate seconds are parsed
+	directives = parseCacheControl([]string{"max-age=1, stale-while-revalidate=30"})
+
+	if directives.staleWhileRevalidate != 30 {
+		t.Fatal("The directives were not as expected", directives)
+	}
+
+	//The end
+	fmt.Println("End TestParseCacheControl")
+
+}
+
+func TestGetFreshness(t *testing.T) {
+
+	now := time.Now()
+
+	//The max-age is used even if it is not the first directive
+	expires, store, _ := getPolicyFreshness(map[string][]string{"Cache-Control": {"public, max-age=60"}}, nil)
+
+	if !store || expires.Sub(now) < 59*time.Second {
+		t.Fatal("The response should be fresh for 60 seconds", expires.Sub(now))
+	}
+
+	//The Age header reduces the freshness
+	expires, store, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"max-age=60"}, "Age": {"50"}}, nil)
+
+	if !store || expires.Sub(now) > 11*time.Second {
+		t.Fatal("The response should be fresh for 10 seconds", expires.Sub(now))
+	}
+
+	//The no-store responses are not stored
+	_, store, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"max-age=60, no-store"}}, nil)
+
+	if store {
+		t.Fatal("The no-store response should not be stored")
+	}
+
+	//The Expires header is used when there is no max-age
+	date := now.UTC().Format(http.TimeFormat)
+	expiresDate := now.Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	expires, store, _ = getPolicyFreshness(map[string][]string{"Expires": {expiresDate}, "Date": {date}}, nil)
+
+	if !store || expires.Sub(now) < 28*time.Second || expires.Sub(now) > 31*time.Second {
+		t.Fatal("The response should be fresh for 30 seconds", expires.Sub(now))
+	}
+
+	//The no-cache responses are stored expired only if they can be revalidated
+	expires, store, mustRevalidate := getPolicyFreshness(map[string][]string{"Cache-Control": {"no-cache"}, "Etag": {"\"v1\""}}, nil)
+
+	if !store || !mustRevalidate || expires.After(time.Now()) {
+		t.Fatal("The no-cache response should be stored expired", store, mustRevalidate)
+	}
+
+	_, store, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"no-cache"}}, nil)
+
+	if store {
+		t.Fatal("The no-cache response without validator should not be stored")
+	}
+
+	//The must-revalidate directive is kept
+	_, _, mustRevalidate = getPolicyFreshness(map[string][]string{"Cache-Control": {"max-age=60, must-revalidate"}}, nil)
+
+	if !mustRevalidate {
+		t.Fatal("The response should be revalidated")
+	}
+
+	//The end
+	fmt.Println("End TestGetFreshness")
+
+}
+
+func TestGetPolicyFreshness(t *testing.T) {
+
+	now := time.Now()
+	policy := &CachePolicy{DefaultTTL: 30000, MinTTL: 10000, MaxTTL: 60000}
+
+	//The responses without freshness use the default time
+	expires, store, _ := getPolicyFreshness(map[string][]string{}, policy)
+
+	if !store || expires.Sub(now) < 29*time.Second || expires.Sub(now) > 31*time.Second {
+		t.Fatal("The response should be fresh for 30 seconds", expires.Sub(now))
+	}
+
+	//The freshness of the headers is clamped
+	expires, _, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"max-age=3600"}}, policy)
+
+	if expires.Sub(now) > 61*time.Second {
+		t.Fatal("The response should be fresh for 60 seconds", expires.Sub(now))
+	}
+
+	expires, _, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"max-age=1"}}, policy)
+
+	if expires.Sub(now) < 9*time.Second {
+		t.Fatal("The response should be fresh for 10 seconds", expires.Sub(now))
+	}
+
+	//The no-store responses are not stored, unless the headers are ignored
+	_, store, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"no-store"}}, policy)
+
+	if store {
+		t.Fatal("The no-store response should not be stored")
+	}
+
+	policy.IgnoreServerHeaders = true
+	expires, store, _ = getPolicyFreshness(map[string][]string{"Cache-Control": {"no-store"}}, policy)
+
+	if !store || expires.Sub(now) < 29*time.Second {
+		t.Fatal("The response should be fresh for 30 seconds ignoring the headers", expires.Sub(now))
+	}
+
+	//The end
+	fmt.Println("End TestGetPolicyFreshness")
+
+}
+
+func TestGetWithCachePolicy(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that caches the responses without headers
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CachePolicy = &CachePolicy{DefaultTTL: 60000}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call twice, the response doesn't have Cache-Control
+	client.Get("http://localhost:8080/counter?id=policy")
+	response, _ := client.Get("http://localhost:8080/counter?id=policy")
+
+	if !response.CachedContent || response.Body != "{\"call\":1}" {
+		t.Fatal("The response should be cached", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithCachePolicy")
+
+}
+
+func TestGetWithMustRevalidate(t *testing.T) {
+
+	//Create a client with stale cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CacheState = true
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the response must be revalidated after 1 second
+	client.Get("http://localhost:8080/cache?seconds=1,%20must-revalidate")
+
+	//Sleep to expire the cached response
+	time.Sleep(1100 * time.Millisecond)
+
+	//Do the GET call with an error, the stale response can't be returned
+	response, _ := client.Get("http://localhost:8080/cache?seconds=1,%20must-revalidate", Header{Key: "Error", Value: "500 error"})
+
+	if response.Staled || response.Code != 500 {
+		t.Fatal("The stale response should not be returned", response.Code)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithMustRevalidate")
+
+}
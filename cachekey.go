@@ -0,0 +1,113 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+//Separator between the url and the headers of a cache key
+const cacheKeySeparator = "\n"
+
+//Return the cache key of the call, adding the request headers that the pool always includes in the key
+func (rclient *rClient) getCacheKey(callURL string, headers map[string]string) string {
+	return addHeadersToKey(rclient.getKeyURL(callURL), rclient.keyHeaders, headers)
+}
+
+//Return the url used in the cache keys of the call, normalized if the pool indicates it
+func (rclient *rClient) getKeyURL(callURL string) string {
+	if rclient.keyFunc != nil {
+		return rclient.keyFunc(callURL)
+	}
+
+	if rclient.normalizeKey {
+		return normalizeURL(callURL, rclient.ignoredParams)
+	}
+
+	return callURL
+}
+
+//Return the url with the scheme and host in lower case, without fragment and ignored params, and with the
+//query params sorted, so the equivalent urls have the same key
+func normalizeURL(callURL string, ignoredParams map[string]bool) string {
+	parsedURL, error := url.Parse(callURL)
+	if error != nil {
+		return callURL
+	}
+
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+	parsedURL.Fragment = ""
+
+	query := parsedURL.Query()
+	for name := range ignoredParams {
+		query.Del(name)
+	}
+
+	//The params are sorted by name, keeping the order of the values of the same param
+	parsedURL.RawQuery = query.Encode()
+	parsedURL.ForceQuery = false
+
+	return parsedURL.String()
+}
+
+//Return the key of the variant of the response selected by the request headers named in the Vary header
+func getVaryKey(key string, vary []string, headers map[string]string) string {
+	return addHeadersToKey(key+cacheKeySeparator+"vary", vary, headers)
+}
+
+func addHeadersToKey(key string, names []string, headers map[string]string) string {
+	if len(names) == 0 {
+		return key
+	}
+
+	requestHeaders := getRequestHeaders(headers)
+
+	var builder strings.Builder
+	builder.WriteString(key)
+
+	for _, name := range names {
+		builder.WriteString(cacheKeySeparator)
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(strings.Join(requestHeaders.Values(name), ","))
+	}
+
+	return builder.String()
+}
+
+//Return the headers sent in a GET call, including the default ones
+func getRequestHeaders(headers map[string]string) http.Header {
+	requestHeaders := make(http.Header)
+	requestHeaders.Set("Accept", "application/json")
+
+	for key, value := range headers {
+		requestHeaders.Set(key, value)
+	}
+
+	return requestHeaders
+}
+
+//Return the sorted and canonical names of a list of headers, and if it includes a wildcard
+func getHeaderNames(values []string) ([]string, bool) {
+	var names []string
+
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+
+			if name == "*" {
+				return nil, true
+			}
+
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, false
+}
@@ -0,0 +1,157 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetWithVaryCache(t *testing.T) {
+
+	//Create a client with cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call in spanish
+	response, _ := client.Get("http://localhost:8080/vary?vary=Accept-Language", Header{Key: "Accept-Language", Value: "es"})
+
+	if response.Body != "{\"language\":\"es\",\"user\":\"\"}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Do the GET call in portuguese, the spanish response can't be used
+	response, _ = client.Get("http://localhost:8080/vary?vary=Accept-Language", Header{Key: "Accept-Language", Value: "pt"})
+
+	if response.Body != "{\"language\":\"pt\",\"user\":\"\"}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Do the GET call in spanish again, getting the cached variant
+	response, _ = client.Get("http://localhost:8080/vary?vary=Accept-Language", Header{Key: "accept-language", Value: "es"})
+
+	if response.Body != "{\"language\":\"es\",\"user\":\"\"}" || !response.CachedContent {
+		t.Fatal("The content was not cached", response.Body)
+	}
+
+	//The responses that vary with any header are not cached
+	client.Get("http://localhost:8080/vary?vary=*")
+	response, _ = client.Get("http://localhost:8080/vary?vary=*")
+
+	if response.CachedContent {
+		t.Fatal("The content should not be cached", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithVaryCache")
+
+}
+
+func TestGetWithCacheKeyHeaders(t *testing.T) {
+
+	//Create a client that includes the Authorization header in the cache key
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CacheKeyHeaders = []string{"authorization"}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call for the first user
+	response, _ := client.Get("http://localhost:8080/vary", Header{Key: "Authorization", Value: "fer"})
+
+	if response.Body != "{\"language\":\"\",\"user\":\"fer\"}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Do the GET call for the second user, the API doesn't send Vary but the response is not shared
+	response, _ = client.Get("http://localhost:8080/vary", Header{Key: "Authorization", Value: "vale"})
+
+	if response.Body != "{\"language\":\"\",\"user\":\"vale\"}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Do the GET call for the first user again, getting the cached response
+	response, _ = client.Get("http://localhost:8080/vary", Header{Key: "Authorization", Value: "fer"})
+
+	if response.Body != "{\"language\":\"\",\"user\":\"fer\"}" || !response.CachedContent {
+		t.Fatal("The content was not cached", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithCacheKeyHeaders")
+
+}
+
+//processRequestVary returns the language and the user of the request, with the Vary sent in the query
+func processRequestVary(w http.ResponseWriter, req *http.Request) {
+
+	if vary := req.URL.Query().Get("vary"); vary != "" {
+		w.Header().Add("Vary", vary)
+	}
+
+	w.Header().Add("Cache-Control", "max-age=10")
+	w.WriteHeader(200)
+	w.Write([]byte(fmt.Sprintf("{\"language\":\"%s\",\"user\":\"%s\"}", req.Header.Get("Accept-Language"), req.Header.Get("Authorization"))))
+
+}
+
+func TestNormalizeURL(t *testing.T) {
+
+	//The scheme and host are case folded, the params sorted and the fragment removed
+	if key := normalizeURL("HTTP://LocalHost:8080/Items?b=2&a=1&a=0#top", nil); key != "http://localhost:8080/Items?a=1&a=0&b=2" {
+		t.Fatal("The url was not normalized", key)
+	}
+
+	//The ignored params are removed
+	if key := normalizeURL("http://localhost:8080/items?utm_source=mail&id=1", map[string]bool{"utm_source": true}); key != "http://localhost:8080/items?id=1" {
+		t.Fatal("The ignored param was not removed", key)
+	}
+
+	//The end
+	fmt.Println("End TestNormalizeURL")
+
+}
+
+func TestGetWithNormalizedCacheKey(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that normalizes the keys, ignoring the tracking param
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CacheKeyIgnoredParams = []string{"utm_source"}
+
+	client.AddCustomPool("/counter", config)
+
+	client.Get("http://localhost:8080/counter?id=normalized&directives=max-age=60")
+
+	//Checks that the equivalent urls get the cached response
+	response, _ := client.Get("http://LOCALHOST:8080/counter?directives=max-age=60&id=normalized&utm_source=mail")
+
+	if !response.CachedContent || response.Body != "{\"call\":1}" {
+		t.Fatal("The response should be cached", response.Body)
+	}
+
+	//Checks that the custom key function is used
+	client.AddCustomPool("/counter", &PoolConfig{CacheElements: 100, CacheKeyFunc: func(callURL string) string {
+		return "counter"
+	}})
+
+	client.Get("http://localhost:8080/counter?id=custom1&directives=max-age=60")
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=custom2&directives=max-age=60"); !response.CachedContent {
+		t.Fatal("The response of the custom key should be cached")
+	}
+
+	//The end
+	fmt.Println("End TestGetWithNormalizedCacheKey")
+
+}
@@ -0,0 +1,121 @@
+package restclient
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+//CacheStats are the counters of the cache of a pool
+type CacheStats struct {
+	//Responses returned from the cache while they were fresh
+	Hits int64
+	//Calls that had to go to the API because there was no fresh response
+	Misses int64
+	//Expired responses returned (while they are refreshed, or because the API failed)
+	StaleServes int64
+	//Expired responses that the API confirmed with a 304
+	Revalidations int64
+	//Responses removed by the size limits of the in memory store or by an invalidation
+	Evictions int64
+	//Elements and bytes (keys and values) saved in the store, the bytes are only known for the in memory store
+	Entries int64
+	Bytes   int64
+}
+
+//CachedKey is a key saved in the cache of a pool
+type CachedKey struct {
+	//Url of the response, followed by the request headers of the key separated by new lines
+	Key string
+	//Time until the response is fresh
+	Expires time.Time
+	//Time until the response can be returned while it is refreshed
+	StaleUntil time.Time
+}
+
+//Counters of the cache of a pool, updated atomically
+type cacheCounters struct {
+	hits          int64
+	misses        int64
+	staleServes   int64
+	revalidations int64
+	evictions     int64
+}
+
+//Implemented by the stores that know their size without reading all the values
+type sizedStore interface {
+	size() (entries int64, bytes int64, evictions int64)
+}
+
+//GetCacheStats returns the stats of every pool with cache, by the pattern of the pool
+func GetCacheStats() map[string]CacheStats {
+	return defaultClient.GetCacheStats()
+}
+
+//GetCachedKeys returns the keys saved in the cache of the pool of the pattern, sorted
+func GetCachedKeys(pattern string) []CachedKey {
+	return defaultClient.GetCachedKeys(pattern)
+}
+
+//GetCacheStats returns the stats of every client pool with cache, by the pattern of the pool
+func (c *Client) GetCacheStats() map[string]CacheStats {
+	stats := make(map[string]CacheStats)
+
+	for pattern, rclient := range c.getPools() {
+		if rclient.cache != nil {
+			stats[pattern] = rclient.cacheStats()
+		}
+	}
+
+	return stats
+}
+
+//GetCachedKeys returns the keys saved in the cache of the client pool of the pattern, sorted
+func (c *Client) GetCachedKeys(pattern string) []CachedKey {
+	rclient := c.getPoolByPattern(pattern)
+	if rclient == nil || rclient.cache == nil {
+		return nil
+	}
+
+	var keys []CachedKey
+
+	for _, key := range rclient.cache.Keys() {
+		//The Vary indexes are not responses
+		element := rclient.getElement(key)
+		if element == nil || element.Vary != nil {
+			continue
+		}
+
+		keys = append(keys, CachedKey{Key: key, Expires: element.Expires, StaleUntil: element.StaleUntil})
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+
+	return keys
+}
+
+//Return the counters of the pool and the size of its store
+func (rclient *rClient) cacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:          atomic.LoadInt64(&rclient.counters.hits),
+		Misses:        atomic.LoadInt64(&rclient.counters.misses),
+		StaleServes:   atomic.LoadInt64(&rclient.counters.staleServes),
+		Revalidations: atomic.LoadInt64(&rclient.counters.revalidations),
+		Evictions:     atomic.LoadInt64(&rclient.counters.evictions),
+	}
+
+	if store, ok := rclient.cache.(sizedStore); ok {
+		entries, bytes, evictions := store.size()
+
+		stats.Entries = entries
+		stats.Bytes = bytes
+		stats.Evictions += evictions
+
+		return stats
+	}
+
+	//The other stores are only counted by their keys, reading all their values to know the bytes would be too slow
+	stats.Entries = int64(len(rclient.cache.Keys()))
+
+	return stats
+}
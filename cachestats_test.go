@@ -0,0 +1,78 @@
+package restclient
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetCacheStats(t *testing.T) {
+
+	//Create a cached pool of 1 element
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 1})
+
+	//Do a miss and a hit
+	client.Get("http://localhost:8080/counter?id=stats1&directives=max-age=60")
+	client.Get("http://localhost:8080/counter?id=stats1&directives=max-age=60")
+
+	//Do a miss that evicts the first response
+	client.Get("http://localhost:8080/counter?id=stats2&directives=max-age=60")
+
+	stats := client.GetCacheStats()["http://localhost:8080/counter.*"]
+
+	//Checks the counters
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Evictions != 1 || stats.Entries != 1 || stats.Bytes == 0 {
+		t.Fatal("The stats were not as expected", stats)
+	}
+
+	//Checks that the cached keys are listed with their expiration
+	keys := client.GetCachedKeys("http://localhost:8080/counter.*")
+
+	if len(keys) != 1 || keys[0].Key != "http://localhost:8080/counter?id=stats2&directives=max-age=60" || keys[0].Expires.Before(time.Now()) {
+		t.Fatal("The keys were not as expected", keys)
+	}
+
+	//Checks that the invalidations are counted as evictions
+	client.InvalidateCache("http://localhost:8080/counter?id=stats2&directives=max-age=60")
+
+	if stats := client.GetCacheStats()["http://localhost:8080/counter.*"]; stats.Evictions != 2 || stats.Entries != 0 {
+		t.Fatal("The stats were not as expected", stats)
+	}
+
+	//The end
+	fmt.Println("End TestGetCacheStats")
+
+}
+
+func TestGetCacheStatsWithFileStore(t *testing.T) {
+
+	//Create a pool cached in files
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheStore: store})
+
+	client.Get("http://localhost:8080/counter?id=stats3&directives=max-age=60")
+
+	//Checks that the entries are counted by their keys, without the bytes
+	stats := client.GetCacheStats()["http://localhost:8080/counter.*"]
+
+	if stats.Misses != 1 || stats.Entries != 1 || stats.Bytes != 0 {
+		t.Fatal("The stats were not as expected", stats)
+	}
+
+	//Checks that the invalidations of the listed keys are counted as evictions
+	client.PurgeCache("http://localhost:8080/counter.*")
+
+	if stats := client.GetCacheStats()["http://localhost:8080/counter.*"]; stats.Evictions != 1 || stats.Entries != 0 {
+		t.Fatal("The stats were not as expected", stats)
+	}
+
+	//The end
+	fmt.Println("End TestGetCacheStatsWithFileStore")
+
+}
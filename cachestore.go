@@ -0,0 +1,313 @@
+package restclient
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+//CacheStore is used to save the cached responses of a pool
+type CacheStore interface {
+	//Get returns the value saved for the key, if it exists and it is not expired
+	Get(key string) ([]byte, bool)
+	//Set saves the value for the key during the ttl, if the ttl is 0 the value doesn't expire
+	Set(key string, value []byte, ttl time.Duration)
+	//Delete removes the value saved for the key
+	Delete(key string)
+	//Keys returns the keys saved in the store
+	Keys() []string
+}
+
+//Time the expired elements are kept for revalidation in the stores that don't evict them, if the pool doesn't set it
+const defaultCacheRetention = 24 * time.Hour
+
+//In memory LRU store, it is the default store of the pools
+type memoryStore struct {
+	mutex     sync.Mutex
+	cache     *simplelru.LRU
+	maxBytes  int64
+	bytes     int64
+	evictions int64
+}
+
+//Entry of the in memory store, it has the value saved with Set or the element saved by the pools
+type memoryEntry struct {
+	value   []byte
+	element *cacheElement
+	size    int64
+	expires time.Time
+}
+
+//Implemented by the stores that keep the elements of the pools without encoding them
+type elementStore interface {
+	getElement(key string) (*cacheElement, bool)
+	setElement(key string, element *cacheElement, size int64, ttl time.Duration) bool
+	removeElement(key string) bool
+}
+
+//ErrCacheMaxBytesWithStore is returned when a pool sets CacheMaxBytes and CacheStore, the size of the store is set
+//when it is created (for example, with NewMemoryStore)
+var ErrCacheMaxBytesWithStore = errors.New("CacheMaxBytes can't be used with CacheStore")
+
+//NewMemoryStore creates an in memory LRU store of the indicated number of elements and, if maxBytes > 0,
+//of the indicated total size of the keys and values
+func NewMemoryStore(size int, maxBytes int64) CacheStore {
+	return newMemoryStore(size, maxBytes)
+}
+
+//Create an in memory LRU store limited by number of elements and, if maxBytes > 0, by the total size of the keys and values
+func newMemoryStore(size int, maxBytes int64) *memoryStore {
+	//Without a limit of elements, only the size is limited
+	if size <= 0 {
+		size = math.MaxInt32
+	}
+
+	store := &memoryStore{maxBytes: maxBytes}
+	store.cache, _ = simplelru.NewLRU(size, store.evicted)
+
+	return store
+}
+
+func (store *memoryStore) Get(key string) ([]byte, bool) {
+	entry := store.get(key)
+	if entry == nil {
+		return nil, false
+	}
+
+	//The elements of the pools are only encoded when they are read as values
+	if entry.element != nil {
+		return encodeElement(entry.element)
+	}
+
+	return entry.value, true
+}
+
+func (store *memoryStore) Set(key string, value []byte, ttl time.Duration) {
+	store.add(key, &memoryEntry{value: value, size: entrySize(key, value)}, ttl)
+}
+
+func (store *memoryStore) getElement(key string) (*cacheElement, bool) {
+	entry := store.get(key)
+	if entry == nil {
+		return nil, false
+	}
+
+	if entry.element != nil {
+		return entry.element, true
+	}
+
+	element := decodeElement(entry.value)
+
+	return element, element != nil
+}
+
+func (store *memoryStore) setElement(key string, element *cacheElement, size int64, ttl time.Duration) bool {
+	return store.add(key, &memoryEntry{element: element, size: int64(len(key)) + size}, ttl)
+}
+
+//Return the entry of the key, if it exists and it is not expired
+func (store *memoryStore) get(key string) *memoryEntry {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	value, ok := store.cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	entry := value.(*memoryEntry)
+
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		store.cache.Remove(key)
+		return nil
+	}
+
+	return entry
+}
+
+//Save the entry during the ttl, evicting the least recently used entries if the store is full. It returns if
+//the entry was saved
+func (store *memoryStore) add(key string, entry *memoryEntry, ttl time.Duration) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	//Remove the previous value, so its size is discounted
+	store.cache.Remove(key)
+
+	//A value bigger than the whole store is not saved
+	if store.maxBytes > 0 && entry.size > store.maxBytes {
+		return false
+	}
+
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	if store.cache.Add(key, entry) {
+		store.evictions++
+	}
+
+	store.bytes += entry.size
+
+	//Evict the least recently used values until the size is under the limit
+	for store.maxBytes > 0 && store.bytes > store.maxBytes {
+		store.cache.RemoveOldest()
+		store.evictions++
+	}
+
+	return true
+}
+
+func (store *memoryStore) Delete(key string) {
+	store.removeElement(key)
+}
+
+//Remove the key, returning if it was saved
+func (store *memoryStore) removeElement(key string) bool {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.cache.Remove(key)
+}
+
+func (store *memoryStore) Keys() []string {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	keys := make([]string, 0, store.cache.Len())
+	for _, key := range store.cache.Keys() {
+		keys = append(keys, key.(string))
+	}
+
+	return keys
+}
+
+func (store *memoryStore) size() (int64, int64, int64) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return int64(store.cache.Len()), store.bytes, store.evictions
+}
+
+//Discount the size of the values removed from the LRU, it is called with the store locked
+func (store *memoryStore) evicted(key interface{}, value interface{}) {
+	store.bytes -= value.(*memoryEntry).size
+}
+
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
+
+//Return the element saved in the store of the pool
+func (rclient *rClient) getElement(key string) *cacheElement {
+	//The in memory store returns the saved element, that must not be changed
+	if store, ok := rclient.cache.(elementStore); ok {
+		element, _ := store.getElement(key)
+		return element
+	}
+
+	value, ok := rclient.cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	return decodeElement(value)
+}
+
+//Decode a value of the store, returning nil if it is corrupt
+func decodeElement(value []byte) *cacheElement {
+	//A value that can't be decoded is treated as a miss
+	element := new(cacheElement)
+	if error := gob.NewDecoder(bytes.NewReader(value)).Decode(element); error != nil {
+		return nil
+	}
+
+	return element
+}
+
+//Encode an element to save it as the value of a store
+func encodeElement(element *cacheElement) ([]byte, bool) {
+	var value bytes.Buffer
+	if error := gob.NewEncoder(&value).Encode(element); error != nil {
+		return nil, false
+	}
+
+	return value.Bytes(), true
+}
+
+//Return the size of the content and headers of the element
+func (element *cacheElement) size() int64 {
+	size := len(element.Content) + len(element.ETag) + len(element.LastModified)
+
+	for name, values := range element.Headers {
+		size += len(name)
+
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+
+	for _, name := range element.Vary {
+		size += len(name)
+	}
+
+	return int64(size)
+}
+
+//Save the element in the store of the pool while it can be used, returning if it was saved
+func (rclient *rClient) setElement(key string, element *cacheElement) bool {
+	store, keepsElements := rclient.cache.(elementStore)
+
+	//The in memory store saves the element as it is, the other stores save it encoded
+	var value []byte
+	var size int64
+
+	if keepsElements {
+		size = element.size()
+	} else {
+		var ok bool
+		if value, ok = encodeElement(element); !ok {
+			return false
+		}
+
+		size = int64(len(value))
+	}
+
+	//The responses bigger than the max size of the pool are not cached
+	if rclient.maxEntryBytes > 0 && size > rclient.maxEntryBytes {
+		rclient.cache.Delete(key)
+		return false
+	}
+
+	//The elements that can be revalidated or returned as staled responses, and the ones with the Vary
+	//names, are kept until the in memory store evicts them, or during the retention of the pool in the
+	//other stores, that don't evict them
+	keep := rclient.stale || element.Vary != nil || element.ETag != "" || element.LastModified != ""
+
+	var ttl time.Duration
+
+	if !keep || !keepsElements {
+		ttl = time.Until(element.StaleUntil)
+
+		if keep {
+			ttl += rclient.retention
+		}
+
+		if ttl <= 0 {
+			return false
+		}
+	}
+
+	if keepsElements {
+		return store.setElement(key, element, size, ttl)
+	}
+
+	rclient.cache.Set(key, value, ttl)
+
+	return true
+}
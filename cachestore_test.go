@@ -0,0 +1,231 @@
+package restclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+
+	//Create a store of 2 elements
+	store := NewMemoryStore(2, 0)
+
+	store.Set("a", []byte("1"), 0)
+	store.Set("b", []byte("2"), 10*time.Millisecond)
+
+	//Checks that the values are saved
+	if value, ok := store.Get("a"); !ok || string(value) != "1" {
+		t.Fatal("The value was not saved", string(value))
+	}
+
+	//Checks that the values expire
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := store.Get("b"); ok {
+		t.Fatal("The value should be expired")
+	}
+
+	//Checks that the oldest values are evicted
+	store.Set("c", []byte("3"), 0)
+	store.Set("d", []byte("4"), 0)
+
+	if _, ok := store.Get("a"); ok {
+		t.Fatal("The value should be evicted")
+	}
+
+	//Checks that the keys are listed
+	if keys := store.Keys(); len(keys) != 2 {
+		t.Fatal("The keys were not as expected", keys)
+	}
+
+	//Checks that the values are deleted
+	store.Delete("d")
+
+	if _, ok := store.Get("d"); ok {
+		t.Fatal("The value should be deleted")
+	}
+
+	//The end
+	fmt.Println("End TestMemoryStore")
+
+}
+
+func TestMemoryStoreElements(t *testing.T) {
+
+	//Create a store and save an element of a pool
+	store := newMemoryStore(10, 0)
+	element := &cacheElement{Content: "{\"id\":\"MLA\"}"}
+
+	store.setElement("a", element, element.size(), 0)
+
+	//Checks that the element is returned without encoding it
+	if saved, ok := store.getElement("a"); !ok || saved != element {
+		t.Fatal("The saved element should be returned")
+	}
+
+	//Checks that the element can be read as a value
+	if value, ok := store.Get("a"); !ok || decodeElement(value).Content != element.Content {
+		t.Fatal("The element should be returned encoded")
+	}
+
+	//Checks that the store reports if it had the removed element
+	if !store.removeElement("a") || store.removeElement("a") {
+		t.Fatal("The element should be removed only once")
+	}
+
+	//The end
+	fmt.Println("End TestMemoryStoreElements")
+
+}
+
+func TestGetWithFileStore(t *testing.T) {
+
+	//Create a file store
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Create two clients sharing the store, like two replicas of a service
+	config := new(PoolConfig)
+	config.CacheStore = store
+
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080", config)
+
+	replica := NewClient()
+	replica.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call with the first client
+	response, _ := client.Get("http://localhost:8080/cache?seconds=10")
+
+	if response.Body != "{\"id\":\"MLA\"}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Do the GET call with the other client, getting the cached response
+	response, _ = replica.Get("http://localhost:8080/cache?seconds=10")
+
+	if response.Body != "{\"id\":\"MLA\"}" || !response.CachedContent {
+		t.Fatal("The content was not cached", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithFileStore")
+
+}
+
+func TestGetWithFileStoreRetention(t *testing.T) {
+
+	//Create a client that returns staled responses, keeping them in a file store for 1 second after they expire
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	config := new(PoolConfig)
+	config.CacheStore = store
+	config.CacheState = true
+	config.CacheRetention = 1000
+
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the response is fresh for 1 second
+	client.Get("http://localhost:8080/cache?seconds=1")
+
+	//Checks that the file expires after the retention, instead of being kept forever
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatal("The response was not saved", len(files))
+	}
+
+	data, _ := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	_, _, expires, _ := decodeFileEntry(data)
+
+	if expires.IsZero() || time.Until(expires) > 2100*time.Millisecond {
+		t.Fatal("The file should expire after the retention", expires)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithFileStoreRetention")
+
+}
+
+func TestMemoryStoreMaxBytes(t *testing.T) {
+
+	//Create a store without limit of elements and 10 bytes of size
+	store := newMemoryStore(0, 10)
+
+	store.Set("a", []byte("1234"), 0)
+	store.Set("b", []byte("1234"), 0)
+
+	if store.bytes != 10 {
+		t.Fatal("The size was not as expected", store.bytes)
+	}
+
+	//Replacing a value discounts the previous size
+	store.Set("b", []byte("12"), 0)
+
+	if store.bytes != 8 {
+		t.Fatal("The size was not as expected", store.bytes)
+	}
+
+	//Adding a value over the size evicts the least recently used one
+	store.Get("a")
+	store.Set("c", []byte("12"), 0)
+
+	if _, ok := store.Get("b"); ok {
+		t.Fatal("The value should be evicted")
+	}
+
+	if _, ok := store.Get("a"); !ok {
+		t.Fatal("The recently used value should not be evicted")
+	}
+
+	//A value bigger than the store is not saved
+	store.Set("d", []byte("12345678901"), 0)
+
+	if _, ok := store.Get("d"); ok || store.bytes != 8 {
+		t.Fatal("The big value should not be saved", store.bytes)
+	}
+
+	//Checks that the size can't be set for a selected store, it is set creating the store
+	if err := NewClient().AddCustomPool("/items/.*", &PoolConfig{CacheStore: NewMemoryStore(0, 10), CacheMaxBytes: 10}); err != ErrCacheMaxBytesWithStore {
+		t.Fatal("We should had got a max bytes error", err)
+	}
+
+	//The end
+	fmt.Println("End TestMemoryStoreMaxBytes")
+
+}
+
+func TestGetWithCacheMaxEntryBytes(t *testing.T) {
+
+	//Create a client that doesn't cache responses bigger than 10 bytes
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheMaxBytes = 1024 * 1024
+	config.CacheMaxEntryBytes = 10
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call twice
+	client.Get("http://localhost:8080/cache?seconds=10")
+	response, _ := client.Get("http://localhost:8080/cache?seconds=10")
+
+	//Checks that the response was not cached
+	if response.CachedContent {
+		t.Fatal("The big response should not be cached")
+	}
+
+	//The end
+	fmt.Println("End TestGetWithCacheMaxEntryBytes")
+
+}
@@ -0,0 +1,161 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//ErrCircuitOpen is returned without calling the API while the circuit breaker of the pool is open
+var ErrCircuitOpen = errors.New("Circuit breaker is open")
+
+//CircuitBreakerConfig is used to define the circuit breaker of a pool
+type CircuitBreakerConfig struct {
+	//Number of consecutive failed calls that opens the circuit (0 disables it)
+	ConsecutiveFailures int
+	//Ratio (0 to 1) of failed calls that opens the circuit (0 disables it)
+	FailureRatio float64
+	//Min number of calls needed to check the failure ratio
+	MinRequests int
+	//Interval in which the calls are counted for the failure ratio (in milliseconds), if 0 they are counted until the circuit opens
+	Interval time.Duration
+	//Time the circuit stays open before letting test calls pass (in milliseconds)
+	CoolDown time.Duration
+	//Number of successful test calls needed to close the circuit again, default 1
+	HalfOpenRequests int
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+//Circuit breaker of a pool
+type circuitBreaker struct {
+	config              CircuitBreakerConfig
+	mutex               sync.Mutex
+	state               circuitState
+	requests            int
+	failures            int
+	consecutiveFailures int
+	halfOpenCalls       int
+	halfOpenSuccesses   int
+	openedAt            time.Time
+	intervalStart       time.Time
+}
+
+func newCircuitBreaker(config *CircuitBreakerConfig) *circuitBreaker {
+	breaker := new(circuitBreaker)
+	breaker.config = *config
+
+	if breaker.config.HalfOpenRequests <= 0 {
+		breaker.config.HalfOpenRequests = 1
+	}
+
+	breaker.intervalStart = time.Now()
+
+	return breaker
+}
+
+//Check if the call can be done, moving the circuit to half-open when the cool down is over
+func (cb *circuitBreaker) allow() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == circuitOpen {
+		if time.Since(cb.openedAt) < cb.config.CoolDown*time.Millisecond {
+			return false
+		}
+
+		cb.state = circuitHalfOpen
+		cb.halfOpenCalls = 0
+		cb.halfOpenSuccesses = 0
+	}
+
+	if cb.state == circuitHalfOpen {
+		//Only let pass the test calls
+		if cb.halfOpenCalls >= cb.config.HalfOpenRequests {
+			return false
+		}
+
+		cb.halfOpenCalls++
+	}
+
+	return true
+}
+
+//Register the result of an allowed call
+func (cb *circuitBreaker) done(response *Response, err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	//The calls cancelled by the caller don't say anything about the API
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		if cb.state == circuitHalfOpen {
+			cb.halfOpenCalls--
+		}
+		return
+	}
+
+	failure := err != nil || response == nil || response.Code >= 500
+
+	switch cb.state {
+	case circuitHalfOpen:
+		if failure {
+			cb.open()
+			return
+		}
+
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.config.HalfOpenRequests {
+			cb.close()
+		}
+
+	case circuitClosed:
+		//Start a new interval of counts if the last one finished
+		if cb.config.Interval > 0 && time.Since(cb.intervalStart) > cb.config.Interval*time.Millisecond {
+			cb.resetCounts()
+		}
+
+		cb.requests++
+
+		if !failure {
+			cb.consecutiveFailures = 0
+			return
+		}
+
+		cb.failures++
+		cb.consecutiveFailures++
+
+		if cb.config.ConsecutiveFailures > 0 && cb.consecutiveFailures >= cb.config.ConsecutiveFailures {
+			cb.open()
+			return
+		}
+
+		if cb.config.FailureRatio > 0 && cb.requests >= cb.config.MinRequests && float64(cb.failures)/float64(cb.requests) >= cb.config.FailureRatio {
+			cb.open()
+		}
+	}
+}
+
+func (cb *circuitBreaker) open() {
+	cb.state = circuitOpen
+	cb.openedAt = time.Now()
+	cb.resetCounts()
+}
+
+func (cb *circuitBreaker) close() {
+	cb.state = circuitClosed
+	cb.resetCounts()
+}
+
+func (cb *circuitBreaker) resetCounts() {
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutiveFailures = 0
+	cb.intervalStart = time.Now()
+}
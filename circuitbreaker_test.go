@@ -0,0 +1,102 @@
+package restclient
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpens(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that opens the circuit after 2 consecutive failures
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CircuitBreaker = &CircuitBreakerConfig{ConsecutiveFailures: 2, CoolDown: 100}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do two failed calls
+	client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+
+	//Checks that the circuit is open
+	_, err := client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	if err != ErrCircuitOpen {
+		t.Fatal("The circuit should be open", err)
+	}
+
+	//Wait for the cool down
+	time.Sleep(150 * time.Millisecond)
+
+	//The test call fails and opens the circuit again
+	response, err := client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	if err != nil || response.Code != 503 {
+		t.Fatal("The test call should reach the server", err)
+	}
+
+	_, err = client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	if err != ErrCircuitOpen {
+		t.Fatal("The circuit should be open again", err)
+	}
+
+	//Wait for the cool down
+	time.Sleep(150 * time.Millisecond)
+
+	//The test call succeeds and closes the circuit
+	response, err = client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	if err != nil || response.Code != 200 {
+		t.Fatal("The test call should succeed", err)
+	}
+
+	response, err = client.Get("http://localhost:8080/flaky?id=breaker&failures=3")
+	if err != nil || response.Code != 200 {
+		t.Fatal("The circuit should be closed", err)
+	}
+
+	//The end
+	fmt.Println("End TestCircuitBreakerOpens")
+
+}
+
+func TestCircuitBreakerWithStaleCache(t *testing.T) {
+
+	//Create a client with stale cache and a circuit breaker by failure ratio
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CacheState = true
+	config.CircuitBreaker = &CircuitBreakerConfig{FailureRatio: 0.5, MinRequests: 2, CoolDown: 10000}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Cache a response for 1 second
+	client.Get("http://localhost:8080/cache?seconds=1")
+
+	//Wait to expire the cached response
+	time.Sleep(1100 * time.Millisecond)
+
+	//Do two failed calls to open the circuit, getting the stale response
+	client.Get("http://localhost:8080/cache?seconds=1", Header{Key: "Error", Value: "500 error"})
+	client.Get("http://localhost:8080/cache?seconds=1", Header{Key: "Error", Value: "500 error"})
+
+	//Checks that the circuit is open for other resources
+	_, err := client.Get("http://localhost:8080/testing")
+	if err != ErrCircuitOpen {
+		t.Fatal("The circuit should be open", err)
+	}
+
+	//Checks that the stale response is returned while the circuit is open
+	response, err := client.Get("http://localhost:8080/cache?seconds=1")
+	if err != nil || !response.Staled {
+		t.Fatal("The stale response should be returned", err)
+	}
+
+	//The end
+	fmt.Println("End TestCircuitBreakerWithStaleCache")
+
+}
@@ -0,0 +1,105 @@
+package restclient
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Call in flight shared by the callers of the same key
+type flightCall struct {
+	done     chan struct{}
+	response *Response
+	error    error
+	waiters  int
+}
+
+//Group of calls in flight of a pool
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+//Execute the call only if there is no other one in flight for the key, otherwise wait for its result.
+//If the call of other caller is cancelled, the waiting callers do their own call
+func (g *flightGroup) do(ctx context.Context, key string, call func() (*Response, error)) (*Response, error) {
+	g.mutex.Lock()
+
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	//Wait for the call in flight
+	if flight, ok := g.calls[key]; ok {
+		flight.waiters++
+		g.mutex.Unlock()
+
+		select {
+		case <-flight.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if flight.error == context.Canceled || flight.error == context.DeadlineExceeded {
+			return call()
+		}
+
+		return sharedResponse(flight.response), flight.error
+	}
+
+	flight := &flightCall{done: make(chan struct{})}
+	g.calls[key] = flight
+	g.mutex.Unlock()
+
+	flight.response, flight.error = call()
+
+	g.mutex.Lock()
+	delete(g.calls, key)
+	shared := flight.waiters > 0
+	g.mutex.Unlock()
+
+	close(flight.done)
+
+	if shared {
+		return sharedResponse(flight.response), flight.error
+	}
+
+	return flight.response, flight.error
+}
+
+//Return a copy of the response marked as shared
+func sharedResponse(response *Response) *Response {
+	if response == nil {
+		return nil
+	}
+
+	shared := *response
+	shared.Shared = true
+
+	return &shared
+}
+
+//Return the key of the calls that can be shared, identical calls have the same url and request headers
+func getFlightKey(key string, headers map[string]string) string {
+	requestHeaders := getRequestHeaders(headers)
+
+	names := make([]string, 0, len(requestHeaders))
+	for name := range requestHeaders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var builder strings.Builder
+	builder.WriteString(key)
+
+	for _, name := range names {
+		builder.WriteString(cacheKeySeparator)
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(strings.Join(requestHeaders[name], ","))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,104 @@
+package restclient
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGetCoalescing(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client with cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do 10 concurrent GET calls of the same resource
+	var wait sync.WaitGroup
+	responses := make(chan *Response, 10)
+
+	for i := 0; i < 10; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			response, _ := client.Get("http://localhost:8080/counter?id=coalescing")
+			responses <- response
+		}()
+	}
+
+	wait.Wait()
+	close(responses)
+
+	//Checks that all the callers got the same shared response
+	for response := range responses {
+		if response.Body != "{\"call\":1}" || !response.Shared {
+			t.Fatal("The response was not shared", response.Body, response.Shared)
+		}
+	}
+
+	//Checks that only one call was done
+	flakyMutex.Lock()
+	calls := flakyCalls["counter-coalescing"]
+	flakyMutex.Unlock()
+
+	if calls != 1 {
+		t.Fatal("Only one call should be done", calls)
+	}
+
+	//A call without other callers is not shared
+	response, _ := client.Get("http://localhost:8080/counter?id=coalescing")
+
+	if response.Shared {
+		t.Fatal("The response should not be shared")
+	}
+
+	//The end
+	fmt.Println("End TestGetCoalescing")
+
+}
+
+func TestGetCoalescingWithDifferentHeaders(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client with cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do 2 concurrent GET calls of the same resource with different headers
+	var wait sync.WaitGroup
+
+	for _, user := range []string{"fer", "vale"} {
+		wait.Add(1)
+		go func(user string) {
+			defer wait.Done()
+			client.Get("http://localhost:8080/counter?id=coalescingheaders", Header{Key: "Authorization", Value: user})
+		}(user)
+	}
+
+	wait.Wait()
+
+	//Checks that both calls were done
+	flakyMutex.Lock()
+	calls := flakyCalls["counter-coalescingheaders"]
+	flakyMutex.Unlock()
+
+	if calls != 2 {
+		t.Fatal("The calls with different headers should not be shared", calls)
+	}
+
+	//The end
+	fmt.Println("End TestGetCoalescingWithDifferentHeaders")
+
+}
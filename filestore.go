@@ -0,0 +1,171 @@
+package restclient
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//File system store, every value is saved in its own file of the directory with its key and expiration
+type fileStore struct {
+	dir string
+}
+
+//NewFileStore creates a store that saves the values in files of the directory, creating it if it doesn't exist
+func NewFileStore(dir string) (CacheStore, error) {
+	if error := os.MkdirAll(dir, 0755); error != nil {
+		return nil, error
+	}
+
+	return &fileStore{dir}, nil
+}
+
+func (store *fileStore) Get(key string) ([]byte, bool) {
+	data, error := ioutil.ReadFile(store.path(key))
+	if error != nil {
+		return nil, false
+	}
+
+	fileKey, value, expires, ok := decodeFileEntry(data)
+
+	//The corrupt files and the files of other keys with the same hash are treated as a miss
+	if !ok || fileKey != key {
+		return nil, false
+	}
+
+	if !expires.IsZero() && time.Now().After(expires) {
+		os.Remove(store.path(key))
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (store *fileStore) Set(key string, value []byte, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	//Write a temporary file and rename it, so the readers never see a partial file
+	file, error := ioutil.TempFile(store.dir, ".tmp-")
+	if error != nil {
+		return
+	}
+
+	_, error = file.Write(encodeFileEntry(key, value, expires))
+	file.Close()
+
+	if error != nil {
+		os.Remove(file.Name())
+		return
+	}
+
+	if error = os.Rename(file.Name(), store.path(key)); error != nil {
+		os.Remove(file.Name())
+	}
+}
+
+func (store *fileStore) Delete(key string) {
+	os.Remove(store.path(key))
+}
+
+func (store *fileStore) Keys() []string {
+	files, error := ioutil.ReadDir(store.dir)
+	if error != nil {
+		return nil
+	}
+
+	var keys []string
+
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".tmp-") {
+			continue
+		}
+
+		if key, ok := readFileKey(filepath.Join(store.dir, file.Name())); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+//Return the file of the key
+func (store *fileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(store.dir, hex.EncodeToString(sum[:]))
+}
+
+//Encode the entry as: expiration in unix nanoseconds (0 if it doesn't expire), key length, key and value
+func encodeFileEntry(key string, value []byte, expires time.Time) []byte {
+	data := make([]byte, 12, 12+len(key)+len(value))
+
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(data[0:8], uint64(expires.UnixNano()))
+	}
+
+	binary.BigEndian.PutUint32(data[8:12], uint32(len(key)))
+
+	data = append(data, key...)
+
+	return append(data, value...)
+}
+
+func decodeFileEntry(data []byte) (string, []byte, time.Time, bool) {
+	if len(data) < 12 {
+		return "", nil, time.Time{}, false
+	}
+
+	var expires time.Time
+	if nanoseconds := binary.BigEndian.Uint64(data[0:8]); nanoseconds != 0 {
+		expires = time.Unix(0, int64(nanoseconds))
+	}
+
+	keyLength := int(binary.BigEndian.Uint32(data[8:12]))
+	if len(data) < 12+keyLength {
+		return "", nil, time.Time{}, false
+	}
+
+	return string(data[12 : 12+keyLength]), data[12+keyLength:], expires, true
+}
+
+//Read only the key of a file, without its value
+func readFileKey(path string) (string, bool) {
+	file, error := os.Open(path)
+	if error != nil {
+		return "", false
+	}
+
+	defer file.Close()
+
+	header := make([]byte, 12)
+	if _, error := io.ReadFull(file, header); error != nil {
+		return "", false
+	}
+
+	info, error := file.Stat()
+	if error != nil {
+		return "", false
+	}
+
+	//A key longer than the file is a corrupt header, it is treated as a miss instead of allocating its length
+	keyLength := int64(binary.BigEndian.Uint32(header[8:12]))
+	if keyLength > info.Size()-12 {
+		return "", false
+	}
+
+	key := make([]byte, keyLength)
+	if _, error := io.ReadFull(file, key); error != nil {
+		return "", false
+	}
+
+	return string(key), true
+}
@@ -0,0 +1,62 @@
+package restclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+
+	//Create a file store
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	store.Set("http://localhost/a", []byte("1"), 0)
+	store.Set("http://localhost/b", []byte("2"), 10*time.Millisecond)
+
+	//Checks that the values are saved
+	if value, ok := store.Get("http://localhost/a"); !ok || string(value) != "1" {
+		t.Fatal("The value was not saved", string(value))
+	}
+
+	//Checks that the values expire
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := store.Get("http://localhost/b"); ok {
+		t.Fatal("The value should be expired")
+	}
+
+	//Checks that the corrupt files are treated as a miss
+	ioutil.WriteFile(store.(*fileStore).path("http://localhost/c"), []byte("corrupt"), 0644)
+
+	if _, ok := store.Get("http://localhost/c"); ok {
+		t.Fatal("The corrupt value should not be returned")
+	}
+
+	//Checks that the files with a key longer than the file are treated as a miss
+	ioutil.WriteFile(store.(*fileStore).path("http://localhost/d"), []byte{0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 'd'}, 0644)
+
+	if _, ok := store.Get("http://localhost/d"); ok {
+		t.Fatal("The corrupt value should not be returned")
+	}
+
+	//Checks that the keys are listed, without the expired and corrupt files
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "http://localhost/a" {
+		t.Fatal("The keys were not as expected", keys)
+	}
+
+	//Checks that the values are deleted
+	store.Delete("http://localhost/a")
+
+	if _, ok := store.Get("http://localhost/a"); ok {
+		t.Fatal("The value should be deleted")
+	}
+
+	//The end
+	fmt.Println("End TestFileStore")
+
+}
@@ -0,0 +1,151 @@
+package restclient
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//HedgePolicy is used to send a second identical GET when the first one of the pool is slow
+type HedgePolicy struct {
+	//Time to wait for the first call before sending the second one (in milliseconds)
+	Delay time.Duration
+	//If > 0, the percentile (0 to 100) of the latencies observed by the pool is used as delay,
+	//until there are enough latencies the Delay is used
+	Percentile float64
+}
+
+//Number of latencies kept by the pool to get the percentile
+const hedgeSamples = 100
+
+//Min number of latencies needed to use the percentile
+const hedgeMinSamples = 20
+
+//Hedging of the GET calls of a pool
+type hedger struct {
+	policy    HedgePolicy
+	mutex     sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+type hedgeResult struct {
+	response *Response
+	error    error
+}
+
+func newHedger(policy *HedgePolicy) *hedger {
+	h := new(hedger)
+	h.policy = *policy
+	h.latencies = make([]time.Duration, 0, hedgeSamples)
+
+	return h
+}
+
+//Save the latency of a successful call
+func (h *hedger) observe(latency time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.latencies) < hedgeSamples {
+		h.latencies = append(h.latencies, latency)
+	} else {
+		h.latencies[h.next] = latency
+		h.next = (h.next + 1) % hedgeSamples
+	}
+}
+
+//Return the time to wait before sending the second call
+func (h *hedger) delay() time.Duration {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.policy.Percentile <= 0 || len(h.latencies) < hedgeMinSamples {
+		return h.policy.Delay * time.Millisecond
+	}
+
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(h.policy.Percentile / 100 * float64(len(sorted)))
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+
+	return sorted[index]
+}
+
+//Send the request, firing a second one if the pool hedges the GET calls and the first one is slow
+func (rclient *rClient) hedgedSend(ctx context.Context, method string, callURL string, body string, headers map[string]string) (*Response, error) {
+	if rclient.hedger == nil || method != http.MethodGet {
+		return rclient.send(ctx, method, callURL, body, headers)
+	}
+
+	//Cancelling the context aborts the call that didn't win
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, 2)
+
+	call := func(hedge bool) {
+		//The second call frees its own slot of the bulkhead
+		if hedge {
+			defer rclient.release()
+		}
+
+		start := time.Now()
+		response, error := rclient.send(hedgeCtx, method, callURL, body, headers)
+
+		if error == nil {
+			rclient.hedger.observe(time.Since(start))
+		}
+
+		results <- hedgeResult{response, error}
+	}
+
+	go call(false)
+
+	timer := time.NewTimer(rclient.hedger.delay())
+	defer timer.Stop()
+
+	//Return the first call if it finishes before the delay
+	select {
+	case result := <-results:
+		return result.response, result.error
+	case <-timer.C:
+		//Without a free slot or token the second call is not sent, so the limits of the pool are kept
+		if !rclient.acquireHedge() {
+			result := <-results
+			return result.response, result.error
+		}
+
+		go call(true)
+	}
+
+	//Return the first successful call, or the last one if both failed
+	result := <-results
+	if result.error == nil {
+		return result.response, nil
+	}
+
+	result = <-results
+
+	return result.response, result.error
+}
+
+//Get a slot of the bulkhead and a token of the rate limit for the second call, without waiting for them
+func (rclient *rClient) acquireHedge() bool {
+	if rclient.bulkhead != nil && !rclient.bulkhead.tryAcquire() {
+		return false
+	}
+
+	if rclient.limiter != nil && !rclient.limiter.tryTake() {
+		rclient.release()
+		return false
+	}
+
+	return true
+}
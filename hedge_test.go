@@ -0,0 +1,132 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHedgedGet(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that sends a second GET after 20ms
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.Hedge = &HedgePolicy{Delay: 20}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	start := time.Now()
+
+	//Do the GET call, the first call takes 1 second and the second one answers immediately
+	response, err := client.Get("http://localhost:8080/hedge?id=first")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks that we got the fast response
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond || response.Body != "{\"call\":2}" {
+		t.Fatal("The hedged response was not returned", elapsed, response.Body)
+	}
+
+	//Checks that the cached response is the returned one
+	cachedResponse, _ := client.Get("http://localhost:8080/hedge?id=first")
+	if !cachedResponse.CachedContent || cachedResponse.Body != "{\"call\":2}" {
+		t.Fatal("The hedged response was not cached", cachedResponse.Body)
+	}
+
+	//The end
+	fmt.Println("End TestHedgedGet")
+
+}
+
+func TestHedgedGetWithBulkhead(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that sends a second GET after 10ms, but allows only 1 call at the same time
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.MaxConcurrentRequests = 1
+	config.Hedge = &HedgePolicy{Delay: 10}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the first call takes 1 second
+	response, err := client.Get("http://localhost:8080/hedge?id=bulkhead")
+
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks that the second call was not sent, because there was no free slot
+	flakyMutex.Lock()
+	calls := flakyCalls["hedge-bulkhead"]
+	flakyMutex.Unlock()
+
+	if calls != 1 || response.Body != "{\"call\":1}" {
+		t.Fatal("The second call should not had been sent", calls, response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestHedgedGetWithBulkhead")
+
+}
+
+func TestHedgerPercentile(t *testing.T) {
+
+	//Create a hedger using the percentile 90
+	h := newHedger(&HedgePolicy{Delay: 50, Percentile: 90})
+
+	//Checks that the delay is used without latencies
+	if h.delay() != 50*time.Millisecond {
+		t.Fatal("The delay was not as expected", h.delay())
+	}
+
+	//Add the latencies from 1ms to 100ms
+	for i := 1; i <= hedgeSamples; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	//Checks that the percentile is used
+	if h.delay() != 91*time.Millisecond {
+		t.Fatal("The percentile was not as expected", h.delay())
+	}
+
+	//The end
+	fmt.Println("End TestHedgerPercentile")
+
+}
+
+//processRequestHedge answers the first call of every id after 1 second
+func processRequestHedge(w http.ResponseWriter, req *http.Request) {
+
+	id := req.URL.Query().Get("id")
+
+	flakyMutex.Lock()
+	flakyCalls["hedge-"+id]++
+	calls := flakyCalls["hedge-"+id]
+	flakyMutex.Unlock()
+
+	if calls == 1 {
+		select {
+		case <-time.After(time.Second):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Add("Cache-Control", "max-age=10")
+	w.WriteHeader(200)
+	w.Write([]byte(fmt.Sprintf("{\"call\":%d}", calls)))
+
+}
@@ -0,0 +1,116 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+//InvalidateCache removes the cached responses of the url (including all its variants) from the cache of its pool
+func InvalidateCache(callURL string) {
+	defaultClient.InvalidateCache(callURL)
+}
+
+//PurgeCache removes the cached responses whose key matches the regular expression from the cache of every pool
+func PurgeCache(pattern string) error {
+	return defaultClient.PurgeCache(pattern)
+}
+
+//InvalidateCache removes the cached responses of the url (including all its variants) from the cache of its client pool
+func (c *Client) InvalidateCache(callURL string) {
+	rclient := c.getPool(callURL)
+
+	if rclient.cache != nil {
+		rclient.invalidate(rclient.getURL(callURL))
+	}
+}
+
+//PurgeCache removes the cached responses whose key matches the regular expression from the cache of every client pool
+func (c *Client) PurgeCache(pattern string) error {
+	expression, error := regexp.Compile(pattern)
+	if error != nil {
+		return error
+	}
+
+	for _, rclient := range c.getPools() {
+		if rclient.cache == nil {
+			continue
+		}
+
+		for _, key := range rclient.cache.Keys() {
+			//The request headers of the key are not matched, only its url
+			if expression.MatchString(strings.SplitN(key, cacheKeySeparator, 2)[0]) {
+				rclient.delete(key, true)
+			}
+		}
+	}
+
+	return nil
+}
+
+//Remove all the elements of the url from the cache, including its variants
+func (rclient *rClient) invalidate(callURL string) {
+	callURL = rclient.getKeyURL(callURL)
+
+	element := rclient.getElement(callURL)
+	rclient.delete(callURL, element != nil)
+
+	//Only look for the other keys of the url if there can be some
+	if len(rclient.keyHeaders) > 0 || (element != nil && element.Vary != nil) {
+		prefix := callURL + cacheKeySeparator
+
+		for _, key := range rclient.cache.Keys() {
+			if strings.HasPrefix(key, prefix) {
+				rclient.delete(key, true)
+			}
+		}
+	}
+}
+
+//Remove the url of a successful unsafe call from the cache, and the urls of its Location and Content-Location headers
+func (rclient *rClient) invalidateAfter(method string, callURL string, response *Response) {
+	if rclient.cache == nil || response == nil || response.Code < 200 || response.Code >= 400 {
+		return
+	}
+
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return
+	}
+
+	rclient.invalidate(callURL)
+
+	base, error := url.Parse(callURL)
+	if error != nil {
+		return
+	}
+
+	for _, header := range []string{"Location", "Content-Location"} {
+		location := http.Header(response.Headers).Get(header)
+		if location == "" {
+			continue
+		}
+
+		//Only the urls of the same host are invalidated
+		if locationURL, error := base.Parse(location); error == nil && locationURL.Host == base.Host {
+			rclient.invalidate(locationURL.String())
+		}
+	}
+}
+
+//Delete the key from the cache, counting it as an eviction if it was saved. The in memory store knows if it
+//had the key, for the other stores the caller tells it (because it read the key, or because Keys listed it)
+func (rclient *rClient) delete(key string, saved bool) {
+	if store, ok := rclient.cache.(elementStore); ok {
+		saved = store.removeElement(key)
+	} else {
+		rclient.cache.Delete(key)
+	}
+
+	if saved {
+		atomic.AddInt64(&rclient.counters.evictions, 1)
+	}
+}
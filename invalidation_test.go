@@ -0,0 +1,117 @@
+package restclient
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInvalidateCache(t *testing.T) {
+
+	//Create a cached pool
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	client.Get("http://localhost:8080/counter?id=invalidate&directives=max-age=60")
+
+	//Checks that the response is cached
+	if response, _ := client.Get("http://localhost:8080/counter?id=invalidate&directives=max-age=60"); !response.CachedContent {
+		t.Fatal("The response should be cached")
+	}
+
+	//Checks that the response is not cached after the invalidation
+	client.InvalidateCache("http://localhost:8080/counter?id=invalidate&directives=max-age=60")
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=invalidate&directives=max-age=60"); response.CachedContent {
+		t.Fatal("The response should not be cached")
+	}
+
+	//The end
+	fmt.Println("End TestInvalidateCache")
+
+}
+
+func TestPurgeCache(t *testing.T) {
+
+	//Create a cached pool
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	client.Get("http://localhost:8080/counter?id=purge1&directives=max-age=60")
+	client.Get("http://localhost:8080/counter?id=purge2&directives=max-age=60")
+
+	//Checks that the invalid patterns return an error
+	if err := client.PurgeCache("[a-"); err == nil {
+		t.Fatal("We should had got an error")
+	}
+
+	//Checks that only the matching responses are removed
+	if err := client.PurgeCache("id=purge1"); err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=purge1&directives=max-age=60"); response.CachedContent {
+		t.Fatal("The purged response should not be cached")
+	}
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=purge2&directives=max-age=60"); !response.CachedContent {
+		t.Fatal("The other response should be cached")
+	}
+
+	//The end
+	fmt.Println("End TestPurgeCache")
+
+}
+
+func TestInvalidateAfterUnsafeCall(t *testing.T) {
+
+	//Create a cached pool
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	client.Get("http://localhost:8080/counter?id=unsafe&directives=max-age=60")
+	client.Get("http://localhost:8080/counter?id=location&directives=max-age=60")
+
+	//Checks that the PUT removes the response of its url
+	client.Put("http://localhost:8080/counter?id=unsafe&directives=max-age=60", "{}")
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=unsafe&directives=max-age=60"); response.CachedContent {
+		t.Fatal("The response should not be cached after the PUT")
+	}
+
+	//Checks that the POST removes the response of its Location
+	client.Post("http://localhost:8080/counter?id=create&location=/counter%3Fid%3Dlocation%26directives%3Dmax-age%3D60", "{}")
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=location&directives=max-age=60"); response.CachedContent {
+		t.Fatal("The response of the Location should not be cached after the POST")
+	}
+
+	//The end
+	fmt.Println("End TestInvalidateAfterUnsafeCall")
+
+}
+
+func TestInvalidateAfterUnsafeStream(t *testing.T) {
+
+	//Create a cached pool
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	client.Get("http://localhost:8080/counter?id=unsafestream&directives=max-age=60")
+
+	//Checks that the streamed PUT removes the response of its url
+	response, err := client.PutStream("http://localhost:8080/counter?id=unsafestream&directives=max-age=60", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	response.Body.Close()
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=unsafestream&directives=max-age=60"); response.CachedContent {
+		t.Fatal("The response should not be cached after the streamed PUT")
+	}
+
+	//The end
+	fmt.Println("End TestInvalidateAfterUnsafeStream")
+
+}
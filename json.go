@@ -0,0 +1,153 @@
+package restclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+//DecodeError is returned when the body of the response can't be decoded into the destination,
+//it keeps the status code and the raw body of the response
+type DecodeError struct {
+	Code int
+	Body string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return "Can't decode the response body (code " + strconv.Itoa(e.Code) + "): " + e.Err.Error()
+}
+
+//Unwrap returns the json error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+//GetJSON execute a HTTP GET call and decodes the JSON response in out
+func GetJSON(callURL string, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.GetJSON(callURL, out, headers...)
+}
+
+//PostJSON execute a HTTP POST call sending in as JSON and decodes the JSON response in out
+func PostJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PostJSON(callURL, in, out, headers...)
+}
+
+//PutJSON execute a HTTP PUT call sending in as JSON and decodes the JSON response in out
+func PutJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PutJSON(callURL, in, out, headers...)
+}
+
+//PatchJSON execute a HTTP PATCH call sending in as JSON and decodes the JSON response in out
+func PatchJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PatchJSON(callURL, in, out, headers...)
+}
+
+//DoJSON execute a HTTP call with any method sending in as JSON and decodes the JSON response in out
+func DoJSON(method string, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.DoJSON(method, callURL, in, out, headers...)
+}
+
+//GetJSONCtx execute a HTTP GET call and decodes the JSON response in out, the call is aborted when the context is done
+func GetJSONCtx(ctx context.Context, callURL string, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.GetJSONCtx(ctx, callURL, out, headers...)
+}
+
+//PostJSONCtx execute a HTTP POST call sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func PostJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PostJSONCtx(ctx, callURL, in, out, headers...)
+}
+
+//PutJSONCtx execute a HTTP PUT call sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func PutJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PutJSONCtx(ctx, callURL, in, out, headers...)
+}
+
+//PatchJSONCtx execute a HTTP PATCH call sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func PatchJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.PatchJSONCtx(ctx, callURL, in, out, headers...)
+}
+
+//DoJSONCtx execute a HTTP call with any method sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func DoJSONCtx(ctx context.Context, method string, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return defaultClient.DoJSONCtx(ctx, method, callURL, in, out, headers...)
+}
+
+//GetJSON execute a HTTP GET call using the client pools and decodes the JSON response in out
+func (c *Client) GetJSON(callURL string, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(context.Background(), http.MethodGet, callURL, nil, out, headers...)
+}
+
+//PostJSON execute a HTTP POST call using the client pools sending in as JSON and decodes the JSON response in out
+func (c *Client) PostJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(context.Background(), http.MethodPost, callURL, in, out, headers...)
+}
+
+//PutJSON execute a HTTP PUT call using the client pools sending in as JSON and decodes the JSON response in out
+func (c *Client) PutJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(context.Background(), http.MethodPut, callURL, in, out, headers...)
+}
+
+//PatchJSON execute a HTTP PATCH call using the client pools sending in as JSON and decodes the JSON response in out
+func (c *Client) PatchJSON(callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(context.Background(), http.MethodPatch, callURL, in, out, headers...)
+}
+
+//DoJSON execute a HTTP call with any method using the client pools sending in as JSON and decodes the JSON response in out
+func (c *Client) DoJSON(method string, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(context.Background(), method, callURL, in, out, headers...)
+}
+
+//GetJSONCtx execute a HTTP GET call using the client pools and decodes the JSON response in out, the call is aborted when the context is done
+func (c *Client) GetJSONCtx(ctx context.Context, callURL string, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(ctx, http.MethodGet, callURL, nil, out, headers...)
+}
+
+//PostJSONCtx execute a HTTP POST call using the client pools sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func (c *Client) PostJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(ctx, http.MethodPost, callURL, in, out, headers...)
+}
+
+//PutJSONCtx execute a HTTP PUT call using the client pools sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func (c *Client) PutJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(ctx, http.MethodPut, callURL, in, out, headers...)
+}
+
+//PatchJSONCtx execute a HTTP PATCH call using the client pools sending in as JSON and decodes the JSON response in out, the call is aborted when the context is done
+func (c *Client) PatchJSONCtx(ctx context.Context, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	return c.DoJSONCtx(ctx, http.MethodPatch, callURL, in, out, headers...)
+}
+
+//DoJSONCtx execute a HTTP call with any method using the client pools, the call is aborted when the context is done.
+//If in is not nil it is sent as the JSON body, and if out is not nil the body of a 2xx response is decoded in it
+//(other responses are returned without decoding)
+func (c *Client) DoJSONCtx(ctx context.Context, method string, callURL string, in interface{}, out interface{}, headers ...Header) (*Response, error) {
+	var body string
+
+	//Encode the body to send
+	if in != nil {
+		byteBody, error := json.Marshal(in)
+
+		if error != nil {
+			return nil, error
+		}
+
+		body = string(byteBody)
+	}
+
+	response, error := c.performRequest(ctx, method, callURL, body, getHeadersMap(headers))
+
+	if error != nil {
+		return response, error
+	}
+
+	//Decode the response only if it was successful and has some content
+	if out != nil && response.Code >= 200 && response.Code < 300 && response.Body != "" {
+		if error := json.Unmarshal([]byte(response.Body), out); error != nil {
+			return response, &DecodeError{response.Code, response.Body, error}
+		}
+	}
+
+	return response, nil
+}
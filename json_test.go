@@ -0,0 +1,118 @@
+package restclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type site struct {
+	ID string `json:"id"`
+}
+
+func TestGetJSON(t *testing.T) {
+
+	//Do the GET call decoding the response
+	var result site
+	response, err := GetJSON("http://localhost:8080/testing", &result)
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the response was 200OK
+	if response.Code != 200 {
+		t.Fatal("There was not 200OK", "200", response.Code)
+	}
+
+	//Checks if the response was decoded
+	if result.ID != "MLA" {
+		t.Fatal("The content was not as expected", "MLA", result.ID)
+	}
+
+	//The end
+	fmt.Println("End TestGetJSON")
+
+}
+
+func TestPostJSON(t *testing.T) {
+
+	//Create a client with a mock that echoes the sent JSON
+	client := NewClient()
+	client.AddMock("http://fer.com/sites", http.MethodPost, "{\"id\":\"FER\"}", Response{Body: "{\"id\":\"FER\"}", Code: 201})
+
+	//Do the POST call encoding the body and decoding the response
+	var result site
+	response, err := client.PostJSON("http://fer.com/sites", site{ID: "FER"}, &result)
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the response was 201
+	if response.Code != 201 {
+		t.Fatal("There was not 201", "201", response.Code)
+	}
+
+	//Checks if the response was decoded
+	if result.ID != "FER" {
+		t.Fatal("The content was not as expected", "FER", result.ID)
+	}
+
+	//The end
+	fmt.Println("End TestPostJSON")
+
+}
+
+func TestGetJSONDecodeError(t *testing.T) {
+
+	//Create a client with a mock that returns an invalid JSON
+	client := NewClient()
+	client.AddMock("http://fer.com/broken", http.MethodGet, "", Response{Body: "<html></html>", Code: 200})
+
+	//Do the GET call
+	var result site
+	_, err := client.GetJSON("http://fer.com/broken", &result)
+
+	//Checks that the error keeps the code and the raw body
+	decodeError, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatal("We should had got a decode error", err)
+	}
+
+	if decodeError.Code != 200 || decodeError.Body != "<html></html>" {
+		t.Fatal("The content was not as expected", "<html></html>", decodeError.Body, decodeError.Code)
+	}
+
+	//The end
+	fmt.Println("End TestGetJSONDecodeError")
+
+}
+
+func TestGetJSONCtx(t *testing.T) {
+
+	//Create a client with a mock
+	client := NewClient()
+	client.AddMock("http://fer.com/sites/FER", http.MethodGet, "", Response{Body: "{\"id\":\"FER\"}", Code: 200})
+
+	//Do the GET call with a context
+	var result site
+	if _, err := client.GetJSONCtx(context.Background(), "http://fer.com/sites/FER", &result); err != nil || result.ID != "FER" {
+		t.Fatal("The content was not as expected", "FER", result.ID, err)
+	}
+
+	//Checks that the cancelled context aborts the call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.DoJSONCtx(ctx, http.MethodGet, "http://localhost:8080/testing", nil, &result); err != context.Canceled {
+		t.Fatal("We should had got a cancel error", err)
+	}
+
+	//The end
+	fmt.Println("End TestGetJSONCtx")
+
+}
@@ -0,0 +1,91 @@
+package restclient
+
+import (
+	"errors"
+)
+
+//ErrPoolNotFound is returned when there is no pool with the pattern
+var ErrPoolNotFound = errors.New("Pool not found")
+
+//RemovePool removes the pool of the pattern, closing its idle connections. The calls in flight finish normally
+//and the next ones use the other pools. It returns false if there was no pool with the pattern.
+func RemovePool(pattern string) bool {
+	return defaultClient.RemovePool(pattern)
+}
+
+//UpdatePool replaces the pool of the pattern with a new one created with the config, closing the idle connections
+//of the old pool. The cache of the old pool is not kept.
+func UpdatePool(pattern string, config *PoolConfig) error {
+	return defaultClient.UpdatePool(pattern, config)
+}
+
+//GetPools returns the config of every pool, by its pattern
+func GetPools() map[string]PoolConfig {
+	return defaultClient.GetPools()
+}
+
+//RemovePool removes the client pool of the pattern, closing its idle connections
+func (c *Client) RemovePool(pattern string) bool {
+	c.poolMutex.Lock()
+
+	rclient := c.pools[pattern]
+	if rclient == nil {
+		c.poolMutex.Unlock()
+		return false
+	}
+
+	delete(c.pools, pattern)
+
+	for i, route := range c.routes {
+		if route.pattern == pattern {
+			c.routes = append(c.routes[:i], c.routes[i+1:]...)
+			break
+		}
+	}
+
+	c.poolMutex.Unlock()
+
+	rclient.client.CloseIdleConnections()
+
+	return true
+}
+
+//UpdatePool replaces the client pool of the pattern with a new one created with the config
+func (c *Client) UpdatePool(pattern string, config *PoolConfig) error {
+	//The pool is checked when it is replaced, so a pool removed meanwhile is not created again
+	return c.addCustomPool(pattern, config, true)
+}
+
+//GetPools returns the config of every client pool, by its pattern
+func (c *Client) GetPools() map[string]PoolConfig {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
+
+	configs := make(map[string]PoolConfig, len(c.pools))
+	for pattern, rclient := range c.pools {
+		configs[pattern] = rclient.config
+	}
+
+	return configs
+}
+
+//Return the pool of the pattern, or nil if there is no pool with the pattern
+func (c *Client) getPoolByPattern(pattern string) *rClient {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
+
+	return c.pools[pattern]
+}
+
+//Return a copy of the pools, so they can be used without locking
+func (c *Client) getPools() map[string]*rClient {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
+
+	pools := make(map[string]*rClient, len(c.pools))
+	for pattern, rclient := range c.pools {
+		pools[pattern] = rclient
+	}
+
+	return pools
+}
@@ -0,0 +1,62 @@
+package restclient
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPoolRegistry(t *testing.T) {
+
+	//Create a client with a pool
+	client := NewClient()
+	client.AddCustomPool("/echo.*", &PoolConfig{Timeout: 1000})
+
+	//Change the pools while the calls are made
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 5; j++ {
+				client.Get("http://localhost:8080/echo")
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		client.UpdatePool("/echo.*", &PoolConfig{Timeout: 2000})
+		client.AddCustomPool("/other.*", &PoolConfig{})
+		client.RemovePool("/other.*")
+	}
+
+	wg.Wait()
+
+	//Checks that the pools are listed with their config
+	pools := client.GetPools()
+
+	if len(pools) != 1 || pools["/echo.*"].Timeout != 2000 {
+		t.Fatal("The pools were not as expected", pools)
+	}
+
+	//Checks that the removed pool is not used
+	if !client.RemovePool("/echo.*") || client.Route("http://localhost:8080/echo") != "default" {
+		t.Fatal("The pool should be removed")
+	}
+
+	if client.RemovePool("/echo.*") {
+		t.Fatal("The pool should not exist")
+	}
+
+	//Checks that only the existing pools can be updated
+	if err := client.UpdatePool("/echo.*", &PoolConfig{}); err != ErrPoolNotFound {
+		t.Fatal("We should had got a pool not found error", err)
+	}
+
+	//The end
+	fmt.Println("End TestPoolRegistry")
+
+}
@@ -0,0 +1,117 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//ErrRateLimited is returned without calling the API when the rate limit of the pool is exceeded
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+//RateLimitConfig is used to define the rate limit of a pool
+type RateLimitConfig struct {
+	//Number of calls per second
+	Rate float64
+	//Max number of calls that can be done at once, default 1
+	Burst int
+	//Wait for the call turn instead of failing, the wait is limited by the deadline of the caller context
+	Wait bool
+}
+
+//Token bucket rate limiter of a pool
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	wait   bool
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(config *RateLimitConfig) *rateLimiter {
+	limiter := new(rateLimiter)
+	limiter.rate = config.Rate
+	limiter.burst = float64(config.Burst)
+	limiter.wait = config.Wait
+
+	if limiter.burst < 1 {
+		limiter.burst = 1
+	}
+
+	//Start with the bucket full
+	limiter.tokens = limiter.burst
+	limiter.last = time.Now()
+
+	return limiter
+}
+
+//Take a token from the bucket, waiting for it if the pool is configured to do it
+func (rl *rateLimiter) take(ctx context.Context) error {
+	rl.mutex.Lock()
+
+	now := time.Now()
+	rl.refill(now)
+
+	//Reserve the token
+	rl.tokens--
+
+	if rl.tokens >= 0 {
+		rl.mutex.Unlock()
+		return nil
+	}
+
+	wait := time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+
+	//Fail if we don't wait or the deadline of the caller is reached before getting the token
+	deadline, hasDeadline := ctx.Deadline()
+
+	if !rl.wait || rl.rate <= 0 || (hasDeadline && now.Add(wait).After(deadline)) {
+		rl.tokens++
+		rl.mutex.Unlock()
+		return ErrRateLimited
+	}
+
+	rl.mutex.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		//Give back the reserved token
+		rl.mutex.Lock()
+		rl.tokens++
+		rl.mutex.Unlock()
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//Take a token from the bucket only if there is one available, without waiting
+func (rl *rateLimiter) tryTake() bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.refill(time.Now())
+
+	if rl.tokens < 1 {
+		return false
+	}
+
+	rl.tokens--
+
+	return true
+}
+
+//Add the tokens generated since the last call, it is called with the limiter locked
+func (rl *rateLimiter) refill(now time.Time) {
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+}
@@ -0,0 +1,107 @@
+package restclient
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRateLimitFail(t *testing.T) {
+
+	//Create a client that allows 2 calls at once and fails when the limit is exceeded
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.RateLimit = &RateLimitConfig{Rate: 1, Burst: 2}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//The burst calls are allowed
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get("http://localhost:8080/echo"); err != nil {
+			t.Fatal("We got an error", err)
+		}
+	}
+
+	//The next call exceeds the limit
+	_, err := client.Get("http://localhost:8080/echo")
+	if err != ErrRateLimited {
+		t.Fatal("We should had got a rate limit error", err)
+	}
+
+	//The end
+	fmt.Println("End TestRateLimitFail")
+
+}
+
+func TestRateLimitWait(t *testing.T) {
+
+	//Create a client that allows 20 calls per second and waits for the turn
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.RateLimit = &RateLimitConfig{Rate: 20, Burst: 1, Wait: true}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	start := time.Now()
+
+	//Do 3 calls, the last two wait 50ms each
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get("http://localhost:8080/echo"); err != nil {
+			t.Fatal("We got an error", err)
+		}
+	}
+
+	//Checks that the calls waited
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatal("The calls should had waited for their turn", elapsed)
+	}
+
+	//A caller with a deadline shorter than the wait fails immediately
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	client.Get("http://localhost:8080/echo")
+
+	_, err := client.GetCtx(ctx, "http://localhost:8080/echo")
+	if err != ErrRateLimited {
+		t.Fatal("We should had got a rate limit error", err)
+	}
+
+	//The end
+	fmt.Println("End TestRateLimitWait")
+
+}
+
+func TestRateLimitWithRetry(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that allows only 1 call and retries the failed ones
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.RateLimit = &RateLimitConfig{Rate: 0.001, Burst: 1}
+	config.Retry = &RetryPolicy{MaxAttempts: 5, BaseBackoff: 1}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the call to an API that always fails
+	response, _ := client.Get("http://localhost:8080/flaky?id=ratelimitretry&failures=10")
+
+	//Checks that the retries were not sent without a token
+	flakyMutex.Lock()
+	calls := flakyCalls["ratelimitretry"]
+	flakyMutex.Unlock()
+
+	if calls != 1 || response == nil || response.Code != 503 {
+		t.Fatal("Only the first call should had been sent", calls)
+	}
+
+	//The end
+	fmt.Println("End TestRateLimitWithRetry")
+
+}
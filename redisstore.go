@@ -0,0 +1,263 @@
+package restclient
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//Timeout of the calls to the Redis server
+const redisTimeout = time.Second
+
+//Max number of idle connections kept by the store
+const redisMaxIdle = 8
+
+//Time the commands fail without dialing after the server couldn't be reached
+const redisRetryDelay = time.Second
+
+//Store that saves the values in a server that speaks the Redis protocol (RESP), the errors are treated as misses
+type redisStore struct {
+	address string
+	prefix  string
+	//Connections that are not in use
+	idle  chan *redisConn
+	mutex sync.Mutex
+	//Until this time the server is considered down
+	downUntil time.Time
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+//NewRedisStore creates a store that saves the values in the Redis server of the address,
+//adding the prefix to the keys so several applications can share the server
+func NewRedisStore(address string, prefix string) CacheStore {
+	return &redisStore{address: address, prefix: prefix, idle: make(chan *redisConn, redisMaxIdle)}
+}
+
+func (store *redisStore) Get(key string) ([]byte, bool) {
+	reply, error := store.command("GET", store.prefix+key)
+	if error != nil {
+		return nil, false
+	}
+
+	value, ok := reply.([]byte)
+
+	return value, ok
+}
+
+func (store *redisStore) Set(key string, value []byte, ttl time.Duration) {
+	if ttl > 0 {
+		//Redis doesn't accept an expiration of 0 milliseconds
+		milliseconds := ttl.Milliseconds()
+		if milliseconds < 1 {
+			milliseconds = 1
+		}
+
+		store.command("SET", store.prefix+key, string(value), "PX", strconv.FormatInt(milliseconds, 10))
+		return
+	}
+
+	store.command("SET", store.prefix+key, string(value))
+}
+
+func (store *redisStore) Delete(key string) {
+	store.command("DEL", store.prefix+key)
+}
+
+func (store *redisStore) Keys() []string {
+	var keys []string
+
+	//Iterate the keys of the prefix with SCAN, so the server is not blocked
+	cursor := "0"
+
+	for {
+		reply, error := store.command("SCAN", cursor, "MATCH", redisPattern(store.prefix)+"*", "COUNT", "1000")
+		if error != nil {
+			return keys
+		}
+
+		values, ok := reply.([]interface{})
+		if !ok || len(values) != 2 {
+			return keys
+		}
+
+		next, _ := values[0].([]byte)
+		found, _ := values[1].([]interface{})
+
+		for _, key := range found {
+			if key, ok := key.([]byte); ok {
+				keys = append(keys, string(key[len(store.prefix):]))
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return keys
+		}
+	}
+}
+
+//Send a command to the server and read its reply, using an idle connection or opening a new one
+func (store *redisStore) command(args ...string) (interface{}, error) {
+	conn, error := store.getConn()
+	if error != nil {
+		return nil, error
+	}
+
+	conn.conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	reply, error := conn.send(args)
+
+	//Close the connection after a network error, the next commands use other ones
+	if _, isReplyError := error.(redisError); error != nil && !isReplyError {
+		conn.conn.Close()
+		return reply, error
+	}
+
+	store.putConn(conn)
+
+	return reply, error
+}
+
+//Return an idle connection, or dial a new one unless the server couldn't be reached recently
+func (store *redisStore) getConn() (*redisConn, error) {
+	select {
+	case conn := <-store.idle:
+		return conn, nil
+	default:
+	}
+
+	store.mutex.Lock()
+	down := time.Now().Before(store.downUntil)
+	store.mutex.Unlock()
+
+	//Fail fast while the server is down, so the calls don't wait for the dial timeout one after the other
+	if down {
+		return nil, errRedisDown
+	}
+
+	conn, error := net.DialTimeout("tcp", store.address, redisTimeout)
+	if error != nil {
+		store.mutex.Lock()
+		store.downUntil = time.Now().Add(redisRetryDelay)
+		store.mutex.Unlock()
+
+		return nil, error
+	}
+
+	return &redisConn{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+//Keep the connection to be reused, closing it if there are enough idle connections
+func (store *redisStore) putConn(conn *redisConn) {
+	select {
+	case store.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+func (conn *redisConn) send(args []string) (interface{}, error) {
+	//Write the command as an array of bulk strings
+	command := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+
+	for _, arg := range args {
+		command = append(command, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		command = append(command, arg...)
+		command = append(command, "\r\n"...)
+	}
+
+	if _, error := conn.conn.Write(command); error != nil {
+		return nil, error
+	}
+
+	return readRedisReply(conn.reader)
+}
+
+//Error returned without dialing while the server is down
+var errRedisDown = errors.New("Redis server is down")
+
+//Error sent by the Redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+//Read a reply of the server: simple strings, errors, integers, bulk strings ([]byte or nil) and arrays
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, error := reader.ReadString('\n')
+	if error != nil {
+		return nil, error
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("Invalid Redis reply")
+	}
+
+	kind, content := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return content, nil
+
+	case '-':
+		return nil, redisError(content)
+
+	case ':':
+		return strconv.ParseInt(content, 10, 64)
+
+	case '$':
+		length, error := strconv.Atoi(content)
+		if error != nil || length < 0 {
+			return nil, error
+		}
+
+		value := make([]byte, length+2)
+		if _, error := io.ReadFull(reader, value); error != nil {
+			return nil, error
+		}
+
+		return value[:length], nil
+
+	case '*':
+		length, error := strconv.Atoi(content)
+		if error != nil || length < 0 {
+			return nil, error
+		}
+
+		values := make([]interface{}, length)
+		for i := range values {
+			if values[i], error = readRedisReply(reader); error != nil {
+				return nil, error
+			}
+		}
+
+		return values, nil
+	}
+
+	return nil, errors.New("Invalid Redis reply")
+}
+
+//Escape the special characters of a Redis glob pattern
+func redisPattern(value string) string {
+	var pattern []byte
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '*', '?', '[', ']', '\\':
+			pattern = append(pattern, '\\')
+		}
+
+		pattern = append(pattern, value[i])
+	}
+
+	return string(pattern)
+}
@@ -0,0 +1,217 @@
+package restclient
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRedisStore(t *testing.T) {
+
+	//Start a local server that speaks the Redis protocol
+	address := startRedisServer(t)
+
+	store := NewRedisStore(address, "test:")
+
+	store.Set("a", []byte("1\r\n2"), 0)
+	store.Set("b", []byte("2"), 10*time.Millisecond)
+
+	//Checks that the values are saved, even with the protocol separators
+	if value, ok := store.Get("a"); !ok || string(value) != "1\r\n2" {
+		t.Fatal("The value was not saved", string(value))
+	}
+
+	//Checks that the values expire
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := store.Get("b"); ok {
+		t.Fatal("The value should be expired")
+	}
+
+	//Checks that the keys are listed without the prefix
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "a" {
+		t.Fatal("The keys were not as expected", keys)
+	}
+
+	//Checks that the values are deleted
+	store.Delete("a")
+
+	if _, ok := store.Get("a"); ok {
+		t.Fatal("The value should be deleted")
+	}
+
+	//Checks that the errors are treated as a miss
+	if _, ok := NewRedisStore("127.0.0.1:1", "").Get("a"); ok {
+		t.Fatal("The value should not be returned without server")
+	}
+
+	//The end
+	fmt.Println("End TestRedisStore")
+
+}
+
+func TestRedisStoreConnections(t *testing.T) {
+
+	//Create a store and use it from several goroutines at the same time
+	store := NewRedisStore(startRedisServer(t), "test:")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := strconv.Itoa(i)
+			store.Set(key, []byte(key), 0)
+
+			if value, ok := store.Get(key); !ok || string(value) != key {
+				t.Error("The value was not saved", string(value))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	//Checks that the commands fail fast after the server couldn't be reached
+	unreachable := NewRedisStore("127.0.0.1:1", "").(*redisStore)
+	unreachable.Get("a")
+
+	if _, err := unreachable.command("GET", "a"); err != errRedisDown {
+		t.Fatal("We should had got a server down error", err)
+	}
+
+	//The end
+	fmt.Println("End TestRedisStoreConnections")
+
+}
+
+func TestGetWithRedisStore(t *testing.T) {
+
+	//Create a client with a Redis store
+	config := new(PoolConfig)
+	config.CacheStore = NewRedisStore(startRedisServer(t), "restclient:")
+
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call
+	client.Get("http://localhost:8080/cache?seconds=10")
+
+	//Do the GET call again, getting the cached response
+	response, _ := client.Get("http://localhost:8080/cache?seconds=10")
+
+	if response.Body != "{\"id\":\"MLA\"}" || !response.CachedContent {
+		t.Fatal("The content was not cached", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithRedisStore")
+
+}
+
+///// Redis stand-in /////
+
+type redisValue struct {
+	value   string
+	expires time.Time
+}
+
+//Start a server that supports the GET, SET (with PX), SCAN and DEL commands, returning its address
+func startRedisServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	values := make(map[string]redisValue)
+	mutex := &sync.Mutex{}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveRedis(conn, values, mutex)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveRedis(conn net.Conn, values map[string]redisValue, mutex *sync.Mutex) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		//Read the command as an array of bulk strings
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		mutex.Lock()
+
+		switch args[0] {
+		case "GET":
+			value, ok := values[args[1]]
+
+			if !ok || (!value.expires.IsZero() && time.Now().After(value.expires)) {
+				conn.Write([]byte("$-1\r\n"))
+			} else {
+				conn.Write([]byte("$" + strconv.Itoa(len(value.value)) + "\r\n" + value.value + "\r\n"))
+			}
+
+		case "SET":
+			value := redisValue{value: args[2]}
+
+			if len(args) == 5 && args[3] == "PX" {
+				milliseconds, _ := strconv.Atoi(args[4])
+				value.expires = time.Now().Add(time.Duration(milliseconds) * time.Millisecond)
+			}
+
+			values[args[1]] = value
+			conn.Write([]byte("+OK\r\n"))
+
+		case "SCAN":
+			//Return all the keys of the pattern in one page
+			prefix := strings.ReplaceAll(strings.TrimSuffix(args[3], "*"), "\\", "")
+			keys := ""
+			count := 0
+
+			for key, value := range values {
+				if strings.HasPrefix(key, prefix) && (value.expires.IsZero() || time.Now().Before(value.expires)) {
+					keys += "$" + strconv.Itoa(len(key)) + "\r\n" + key + "\r\n"
+					count++
+				}
+			}
+
+			conn.Write([]byte("*2\r\n$1\r\n0\r\n*" + strconv.Itoa(count) + "\r\n" + keys))
+
+		case "DEL":
+			delete(values, args[1])
+			conn.Write([]byte(":1\r\n"))
+
+		default:
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+
+		mutex.Unlock()
+	}
+}
@@ -6,18 +6,18 @@ package restclient
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"sync"
-
-	"github.com/hashicorp/golang-lru"
+	"sync/atomic"
 )
 
 //Response is a struct that holds the information about the response of the call
@@ -27,24 +27,78 @@ type Response struct {
 	Headers       map[string][]string
 	CachedContent bool
 	Staled        bool
+	//Number of calls made to get the response (0 if it came from a mock or the cache)
+	Attempts int
+	//Indicates that the response was shared by several concurrent calls
+	Shared bool
 }
 
 //Rest Client (with cache) struct
 type rClient struct {
-	client  *http.Client
-	baseURL string
-	cache   *lru.Cache
-	stale   bool
+	client   *http.Client
+	baseURL  string
+	cache    CacheStore
+	stale    bool
+	timeout  time.Duration
+	retry    *RetryPolicy
+	breaker  *circuitBreaker
+	limiter  *rateLimiter
+	bulkhead *bulkhead
+	hedger   *hedger
+	//Request headers included in every cache key
+	keyHeaders []string
+	//Time the expired elements can be returned while they are refreshed, overrides the stale-while-revalidate directive
+	staleWhileRevalidate time.Duration
+	//Keys of the elements that are being refreshed in background
+	refreshing   map[string]bool
+	refreshMutex sync.Mutex
+	//GET calls in flight
+	flights flightGroup
+	//Max size of a cached response
+	maxEntryBytes int64
+	//Time the expired elements are kept for revalidation in the stores that don't evict them
+	retention time.Duration
+	//Stats of the cache
+	counters cacheCounters
+	//Time the responses of other status codes than 200 are cached
+	statusTTL map[int]time.Duration
+	//How long the responses are cached besides their headers
+	cachePolicy *CachePolicy
+	//Config used to create the pool
+	config PoolConfig
+	//Normalization of the urls of the cache keys
+	normalizeKey  bool
+	ignoredParams map[string]bool
+	keyFunc       func(callURL string) string
 }
 
 //PoolConfig is used to define a custom configuration for the pool
 type PoolConfig struct {
-	BaseURL             string
-	MaxIdleConnsPerHost int
-	Timeout             time.Duration
-	Proxy               string
-	CacheElements       int
-	CacheState          bool
+	BaseURL               string
+	MaxIdleConnsPerHost   int
+	Timeout               time.Duration
+	Proxy                 string
+	CacheElements         int
+	CacheState            bool
+	Retry                 *RetryPolicy
+	CircuitBreaker        *CircuitBreakerConfig
+	RateLimit             *RateLimitConfig
+	MaxConcurrentRequests int
+	MaxQueuedRequests     int
+	QueueTimeout          time.Duration
+	Hedge                 *HedgePolicy
+	CacheKeyHeaders       []string
+	StaleWhileRevalidate  time.Duration
+	CacheStore            CacheStore
+	CacheMaxBytes         int64
+	CacheMaxEntryBytes    int64
+	CacheRetention        time.Duration
+	CacheStatusCodes      map[int]time.Duration
+	CachePolicy           *CachePolicy
+	CacheKeyNormalize     bool
+	CacheKeyIgnoredParams []string
+	CacheKeyFunc          func(callURL string) string
+	Priority              int
 }
 
 type Header struct {
@@ -58,11 +112,36 @@ func (e *NotFollowRedirectError) Error() string {
 	return "Don't follow redirect"
 }
 
+//TimeoutError is returned when the pool Timeout is exceeded before the call finishes,
+//a cancelled or expired caller context returns the context error instead
+type TimeoutError struct {
+	URL         string
+	PoolTimeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return "Pool timeout of " + e.PoolTimeout.String() + " exceeded calling " + e.URL
+}
+
+//Timeout reports that the error was caused by a timeout
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
 //Cache node
 type cacheElement struct {
-	Content string
-	Headers map[string][]string
-	Expires time.Time
+	Content        string
+	Headers        map[string][]string
+	Expires        time.Time
+	ETag           string
+	LastModified   string
+	MustRevalidate bool
+	//Status code of the response, 0 in the elements saved before it was added means 200
+	Code int
+	//Request headers that select the variant of the response, only set in the element saved in the url key
+	Vary []string
+	//Until this time the expired element can be returned while it is refreshed in background
+	StaleUntil time.Time
 }
 
 //internal structure for mocks
@@ -75,14 +154,25 @@ type mockResponse struct {
 	Body     string
 }
 
-//Connections pools
-var pools = make(map[string]*rClient)
-
-//List of mocks
-var mocks []*mockResponse
+//Client is a rest client that owns its connection pools, caches and mocks.
+//The zero value is ready to use, without mocks, and a Client must not be copied after its first use.
+type Client struct {
+	//Connections pools
+	pools map[string]*rClient
+	//Patterns of the pools, in the order they are evaluated
+	routes     []*poolRoute
+	routeCount int
+	//Protects the pools and routes, that can be changed while the calls are made
+	poolMutex sync.RWMutex
+	//List of mocks
+	mocks []*mockResponse
+	//indicates if we have to use the mocks
+	useMock bool
+	mutex   sync.Mutex
+}
 
-//indicates if we have to use the mocks
-var useMock bool
+//Client used by the package level functions
+var defaultClient = NewClient()
 
 var notFollowRedirectError *NotFollowRedirectError = new(NotFollowRedirectError)
 
@@ -90,8 +180,44 @@ const (
 	DEFAULT_MAX_IDLE_CONNECTIONS_PER_HOST = 100
 )
 
-//AddCustomPool create a new connection pool based on the sent parameters
-func AddCustomPool(pattern string, config *PoolConfig) {
+//NewClient creates a rest client with its own connection pools, caches and mocks
+func NewClient() *Client {
+	client := new(Client)
+
+	//If we are not in production, use the mocks
+	client.useMock = os.Getenv("GO_ENVIRONMENT") != "production"
+
+	return client
+}
+
+//AddCustomPool create a new connection pool based on the sent parameters, used by the calls whose url matches the
+//pattern (or by the calls that don't match any pattern if it is "default"). It fails if the pattern is invalid.
+func AddCustomPool(pattern string, config *PoolConfig) error {
+	return defaultClient.AddCustomPool(pattern, config)
+}
+
+//AddCustomPool create a new connection pool in the client based on the sent parameters
+func (c *Client) AddCustomPool(pattern string, config *PoolConfig) error {
+	return c.addCustomPool(pattern, config, false)
+}
+
+//Create the pool of the pattern, if mustExist it only replaces an existing pool and returns ErrPoolNotFound otherwise
+func (c *Client) addCustomPool(pattern string, config *PoolConfig, mustExist bool) error {
+	//Compile the pattern once, so an invalid one is reported here instead of in every call
+	var expression *regexp.Regexp
+
+	if pattern != defaultPoolName {
+		var error error
+		if expression, error = regexp.Compile(pattern); error != nil {
+			return error
+		}
+	}
+
+	//The size of a selected store is set when it is created
+	if config.CacheStore != nil && config.CacheMaxBytes > 0 {
+		return ErrCacheMaxBytesWithStore
+	}
+
 	//Create a transport for the connection
 	transport := defaultTransport()
 
@@ -124,68 +250,281 @@ func AddCustomPool(pattern string, config *PoolConfig) {
 	//Creates the client-cache struct
 	rclient := new(rClient)
 	rclient.client = client
+	rclient.timeout = client.Timeout
 
 	if config.BaseURL != "" {
 		rclient.baseURL = config.BaseURL
 	}
 
-	//Create the cache if it was indicated
-	if config.CacheElements > 0 {
-		cache, _ := lru.New(config.CacheElements)
-		rclient.cache = cache
+	//Copy the retry policy, so it can't be changed after the pool is created
+	if config.Retry != nil {
+		retry := *config.Retry
+		rclient.retry = &retry
+	}
+
+	//Create the circuit breaker if it was indicated
+	if config.CircuitBreaker != nil {
+		rclient.breaker = newCircuitBreaker(config.CircuitBreaker)
+	}
+
+	//Create the rate limiter if it was indicated
+	if config.RateLimit != nil {
+		rclient.limiter = newRateLimiter(config.RateLimit)
+	}
+
+	//Create the bulkhead if it was indicated
+	if config.MaxConcurrentRequests > 0 {
+		rclient.bulkhead = newBulkhead(config.MaxConcurrentRequests, config.MaxQueuedRequests, config.QueueTimeout)
+	}
+
+	//Create the hedging of the GET calls if it was indicated
+	if config.Hedge != nil {
+		rclient.hedger = newHedger(config.Hedge)
+	}
+
+	//Create the cache if it was indicated, using the in memory store if no other was selected
+	if config.CacheStore != nil || config.CacheElements > 0 || config.CacheMaxBytes > 0 {
+		rclient.cache = config.CacheStore
+
+		if rclient.cache == nil {
+			rclient.cache = newMemoryStore(config.CacheElements, config.CacheMaxBytes)
+		}
+
+		rclient.maxEntryBytes = config.CacheMaxEntryBytes
+
+		rclient.retention = defaultCacheRetention
+		if config.CacheRetention > 0 {
+			rclient.retention = config.CacheRetention * time.Millisecond
+		}
+
 		rclient.stale = config.CacheState
+		rclient.keyHeaders, _ = getHeaderNames(config.CacheKeyHeaders)
+		rclient.staleWhileRevalidate = config.StaleWhileRevalidate * time.Millisecond
+
+		//Copy the cache policy, so it can't be changed after the pool is created
+		if config.CachePolicy != nil {
+			policy := *config.CachePolicy
+			rclient.cachePolicy = &policy
+		}
+
+		//Normalize the urls of the keys if it was indicated, the ignored params imply it
+		rclient.keyFunc = config.CacheKeyFunc
+		rclient.normalizeKey = config.CacheKeyNormalize || len(config.CacheKeyIgnoredParams) > 0
+
+		rclient.ignoredParams = make(map[string]bool, len(config.CacheKeyIgnoredParams))
+		for _, name := range config.CacheKeyIgnoredParams {
+			rclient.ignoredParams[name] = true
+		}
+
+		//Copy the status codes, so they can't be changed after the pool is created
+		rclient.statusTTL = make(map[int]time.Duration, len(config.CacheStatusCodes))
+		for code, ttl := range config.CacheStatusCodes {
+			if code != http.StatusOK && ttl > 0 {
+				rclient.statusTTL[code] = ttl * time.Millisecond
+			}
+		}
+	}
+
+	//Save the config, so the pools can be listed
+	rclient.config = *config
+
+	//save the pool, closing the idle connections of the pool it replaces
+	previous, error := c.addPool(pattern, expression, config.Priority, rclient, mustExist)
+	if error != nil {
+		return error
+	}
+
+	if previous != nil {
+		previous.client.CloseIdleConnections()
 	}
 
-	//save the pool
-	pools[pattern] = rclient
+	return nil
 }
 
 //Get execute a HTTP GET call to the specified url using headers to forward
 func Get(callURL string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodGet, callURL, "", getHeadersMap(headers))
+	return defaultClient.Get(callURL, headers...)
 }
 
 //Post execute a HTTP POST call to the specified url using headers to forward
 func Post(callURL string, body string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodPost, callURL, body, getHeadersMap(headers))
+	return defaultClient.Post(callURL, body, headers...)
 }
 
 //Put execute a HTTP PUT call to the specified url using headers to forward
 func Put(callURL string, body string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodPut, callURL, body, getHeadersMap(headers))
+	return defaultClient.Put(callURL, body, headers...)
 }
 
 //Delete execute a HTTP DELETE call to the specified url using headers to forward
 func Delete(callURL string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodDelete, callURL, "", getHeadersMap(headers))
+	return defaultClient.Delete(callURL, headers...)
 }
 
 //Head execute a HTTP HEAD call to the specified url using headers to forward
 func Head(callURL string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodHead, callURL, "", getHeadersMap(headers))
+	return defaultClient.Head(callURL, headers...)
 }
 
 //Options execute a HTTP OPTIONS call to the specified url using headers to forward
 func Options(callURL string, headers ...Header) (*Response, error) {
-	return performRequest(http.MethodOptions, callURL, "", getHeadersMap(headers))
+	return defaultClient.Options(callURL, headers...)
+}
+
+//Patch execute a HTTP PATCH call to the specified url using headers to forward
+func Patch(callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.Patch(callURL, body, headers...)
+}
+
+//Do execute a HTTP call with any method to the specified url, the body is sent whenever it is not empty
+func Do(method string, callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.Do(method, callURL, body, headers...)
+}
+
+//Get execute a HTTP GET call to the specified url using the client pools
+func (c *Client) Get(callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodGet, callURL, "", getHeadersMap(headers))
+}
+
+//Post execute a HTTP POST call to the specified url using the client pools
+func (c *Client) Post(callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodPost, callURL, body, getHeadersMap(headers))
+}
+
+//Put execute a HTTP PUT call to the specified url using the client pools
+func (c *Client) Put(callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodPut, callURL, body, getHeadersMap(headers))
+}
+
+//Delete execute a HTTP DELETE call to the specified url using the client pools
+func (c *Client) Delete(callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodDelete, callURL, "", getHeadersMap(headers))
+}
+
+//Head execute a HTTP HEAD call to the specified url using the client pools
+func (c *Client) Head(callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodHead, callURL, "", getHeadersMap(headers))
+}
+
+//Options execute a HTTP OPTIONS call to the specified url using the client pools
+func (c *Client) Options(callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodOptions, callURL, "", getHeadersMap(headers))
+}
+
+//Patch execute a HTTP PATCH call to the specified url using the client pools
+func (c *Client) Patch(callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), http.MethodPatch, callURL, body, getHeadersMap(headers))
+}
+
+//Do execute a HTTP call with any method using the client pools, the body is sent whenever it is not empty
+func (c *Client) Do(method string, callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(context.Background(), method, callURL, body, getHeadersMap(headers))
+}
+
+//GetCtx execute a HTTP GET call to the specified url, the call is aborted when the context is done
+func GetCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return defaultClient.GetCtx(ctx, callURL, headers...)
+}
+
+//PostCtx execute a HTTP POST call to the specified url, the call is aborted when the context is done
+func PostCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.PostCtx(ctx, callURL, body, headers...)
+}
+
+//PutCtx execute a HTTP PUT call to the specified url, the call is aborted when the context is done
+func PutCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.PutCtx(ctx, callURL, body, headers...)
+}
+
+//DeleteCtx execute a HTTP DELETE call to the specified url, the call is aborted when the context is done
+func DeleteCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return defaultClient.DeleteCtx(ctx, callURL, headers...)
+}
+
+//HeadCtx execute a HTTP HEAD call to the specified url, the call is aborted when the context is done
+func HeadCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return defaultClient.HeadCtx(ctx, callURL, headers...)
+}
+
+//OptionsCtx execute a HTTP OPTIONS call to the specified url, the call is aborted when the context is done
+func OptionsCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return defaultClient.OptionsCtx(ctx, callURL, headers...)
+}
+
+//PatchCtx execute a HTTP PATCH call to the specified url, the call is aborted when the context is done
+func PatchCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.PatchCtx(ctx, callURL, body, headers...)
+}
+
+//DoCtx execute a HTTP call with any method to the specified url, the call is aborted when the context is done
+func DoCtx(ctx context.Context, method string, callURL string, body string, headers ...Header) (*Response, error) {
+	return defaultClient.DoCtx(ctx, method, callURL, body, headers...)
+}
+
+//GetCtx execute a HTTP GET call using the client pools, the call is aborted when the context is done
+func (c *Client) GetCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodGet, callURL, "", getHeadersMap(headers))
+}
+
+//PostCtx execute a HTTP POST call using the client pools, the call is aborted when the context is done
+func (c *Client) PostCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodPost, callURL, body, getHeadersMap(headers))
+}
+
+//PutCtx execute a HTTP PUT call using the client pools, the call is aborted when the context is done
+func (c *Client) PutCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodPut, callURL, body, getHeadersMap(headers))
+}
+
+//DeleteCtx execute a HTTP DELETE call using the client pools, the call is aborted when the context is done
+func (c *Client) DeleteCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodDelete, callURL, "", getHeadersMap(headers))
+}
+
+//HeadCtx execute a HTTP HEAD call using the client pools, the call is aborted when the context is done
+func (c *Client) HeadCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodHead, callURL, "", getHeadersMap(headers))
+}
+
+//OptionsCtx execute a HTTP OPTIONS call using the client pools, the call is aborted when the context is done
+func (c *Client) OptionsCtx(ctx context.Context, callURL string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodOptions, callURL, "", getHeadersMap(headers))
+}
+
+//PatchCtx execute a HTTP PATCH call using the client pools, the call is aborted when the context is done
+func (c *Client) PatchCtx(ctx context.Context, callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, http.MethodPatch, callURL, body, getHeadersMap(headers))
+}
+
+//DoCtx execute a HTTP call with any method using the client pools, the call is aborted when the context is done
+func (c *Client) DoCtx(ctx context.Context, method string, callURL string, body string, headers ...Header) (*Response, error) {
+	return c.performRequest(ctx, method, callURL, body, getHeadersMap(headers))
 }
 
 //AddMocks add seveal GET mocks for simple testing
 func AddMocks(mocks map[string]Response) {
+	defaultClient.AddMocks(mocks)
+}
+
+//AddMocks add seveal GET mocks to the client for simple testing
+func (c *Client) AddMocks(mocks map[string]Response) {
 	//All all mocks in a bulk
 	for key, value := range mocks {
 		//fmt.Println("mock: ", key," - ", value)
-		AddMock(key, http.MethodGet, "", value)
+		c.AddMock(key, http.MethodGet, "", value)
 	}
 }
 
-var mutex = &sync.Mutex{}
-
 //AddMock add a URL, headers and Response to the mock URL map
 func AddMock(URL string, method string, body string, response Response, headers ...Header) {
+	defaultClient.AddMock(URL, method, body, response, headers...)
+}
+
+//AddMock add a URL, headers and Response to the client mock URL map
+func (c *Client) AddMock(URL string, method string, body string, response Response, headers ...Header) {
 
 	//If we are un production dont't load the mocks
-	if !useMock {
+	if !c.useMock {
 		return
 	}
 
@@ -197,86 +536,239 @@ func AddMock(URL string, method string, body string, response Response, headers
 	mResponse.Body = body
 	mResponse.URL = URL
 
-	mutex.Lock()
+	c.mutex.Lock()
 	//Creste the map if doesnt exists of add the mock to the existing one
-	if mocks == nil {
-		mocks = make([]*mockResponse, 1)
-		mocks[0] = mResponse
+	if c.mocks == nil {
+		c.mocks = make([]*mockResponse, 1)
+		c.mocks[0] = mResponse
 	} else {
-		mocks = append(mocks, mResponse)
+		c.mocks = append(c.mocks, mResponse)
 	}
-	mutex.Unlock()
+	c.mutex.Unlock()
 
 }
 
 //URLasRegexp mark the URL to be evaluated as Regexp
 func URLasRegexp(url string) {
+	defaultClient.URLasRegexp(url)
+}
+
+//URLasRegexp mark the client mock URL to be evaluated as Regexp
+func (c *Client) URLasRegexp(url string) {
 
-	mutex.Lock()
-	for _, mock := range mocks {
+	c.mutex.Lock()
+	for _, mock := range c.mocks {
 		if mock.URL == url {
 			mock.Regexp = true
 		}
 	}
-	mutex.Unlock()
+	c.mutex.Unlock()
 }
 
 //Clean mocks
 func CleanMocks() {
-	mutex.Lock()
-	mocks = nil
-	mutex.Unlock()
+	defaultClient.CleanMocks()
+}
+
+//CleanMocks remove all the client mocks
+func (c *Client) CleanMocks() {
+	c.mutex.Lock()
+	c.mocks = nil
+	c.mutex.Unlock()
 }
 
 //DisableMock disable all the URL mocks
 func DisableMock() {
-	useMock = false
+	defaultClient.DisableMock()
+}
+
+//DisableMock disable all the client URL mocks
+func (c *Client) DisableMock() {
+	c.useMock = false
 }
 
 //UseMock inform if we are using mocks or not
 func UseMock() bool {
-	return useMock
+	return defaultClient.UseMock()
+}
+
+//UseMock inform if the client is using mocks or not
+func (c *Client) UseMock() bool {
+	return c.useMock
 }
 
 //Execute the request
-func performRequest(method string, callURL string, body string, headers map[string]string) (*Response, error) {
+func (c *Client) performRequest(ctx context.Context, method string, callURL string, body string, headers map[string]string) (*Response, error) {
 	//Get the rClient for the url
-	rclient := getPool(callURL)
+	rclient := c.getPool(callURL)
 
-	if rclient.baseURL != "" && !strings.Contains(callURL, rclient.baseURL) {
-		callURL = rclient.baseURL + callURL
-	}
+	callURL = rclient.getURL(callURL)
 
 	//If theere is a mock for the url and we are in testing, return the mock response
-	if useMock {
-		r := searchMockCall(method, callURL, headers, body)
+	if c.useMock {
+		r := c.searchMockCall(method, callURL, headers, body)
 		if r != nil {
 			return r, nil
 		}
 	}
 
 	//Chech if we have to use the cache
-	withCache := method == http.MethodGet && rclient.cache != nil
+	if method == http.MethodGet && rclient.cache != nil {
+		return rclient.cachedGet(ctx, callURL, headers)
+	}
 
-	var cachedResponse *Response
+	rcResponse, error := rclient.call(ctx, method, callURL, body, headers)
 
-	if withCache {
-		cachedResponse = getResponseFromCache(rclient, callURL)
+	//The successful unsafe calls change the resource, so its cached responses are removed
+	rclient.invalidateAfter(method, callURL, rcResponse)
 
-		if cachedResponse != nil && !cachedResponse.Staled {
+	return rcResponse, error
+}
+
+//Execute a GET call using the cache of the pool
+func (rclient *rClient) cachedGet(ctx context.Context, callURL string, headers map[string]string) (*Response, error) {
+	cacheKey := rclient.getCacheKey(callURL, headers)
+	element, elementKey := getCacheElement(rclient, cacheKey, headers)
+	cachedResponse := getResponseFromCache(rclient, element)
+
+	if cachedResponse != nil && !cachedResponse.Staled {
+		atomic.AddInt64(&rclient.counters.hits, 1)
+		return cachedResponse, nil
+	}
+
+	//Return the expired element while it is refreshed in background
+	if element != nil && !element.MustRevalidate && time.Now().Before(element.StaleUntil) {
+		rclient.refreshInBackground(callURL, headers, cacheKey, element, elementKey)
+		atomic.AddInt64(&rclient.counters.staleServes, 1)
+
+		return &Response{Body: element.Content, Code: element.code(), Headers: element.Headers, CachedContent: true, Staled: true}, nil
+	}
+
+	atomic.AddInt64(&rclient.counters.misses, 1)
+
+	//Share the call with the other callers waiting for the same response
+	return rclient.flights.do(ctx, getFlightKey(elementKey, headers), func() (*Response, error) {
+		return rclient.fetch(ctx, callURL, headers, cacheKey, element, elementKey, cachedResponse)
+	})
+}
+
+//Get the response from the API and save it in the cache, revalidating the expired element if there is one
+func (rclient *rClient) fetch(ctx context.Context, callURL string, headers map[string]string, cacheKey string, element *cacheElement, elementKey string, cachedResponse *Response) (*Response, error) {
+	//Ask the API if the expired element is still valid
+	conditionalHeaders, revalidating := revalidationHeaders(element, headers)
+
+	rcResponse, error := rclient.call(ctx, http.MethodGet, callURL, "", conditionalHeaders)
+
+	//The expired element is still valid, so we refresh it
+	if revalidating && rcResponse != nil && rcResponse.Code == http.StatusNotModified {
+		atomic.AddInt64(&rclient.counters.revalidations, 1)
+		return refreshCacheElement(rclient, element, rcResponse, elementKey), nil
+	}
+
+	//Chek if we got 200OK or other status code cached by the pool
+	if rcResponse != nil && (rcResponse.Code == http.StatusOK || rclient.statusTTL[rcResponse.Code] > 0) {
+		setResponseInCache(rclient, rcResponse, cacheKey, headers)
+
+	} else {
+		//If we got some error and the state option is configured, return the last good cached response
+		if rclient.stale && cachedResponse != nil {
+			atomic.AddInt64(&rclient.counters.staleServes, 1)
 			return cachedResponse, nil
 		}
 	}
 
+	return rcResponse, error
+}
+
+//Refresh the expired element without blocking the caller, only one refresh per key is done at the same time
+func (rclient *rClient) refreshInBackground(callURL string, headers map[string]string, cacheKey string, element *cacheElement, elementKey string) {
+	rclient.refreshMutex.Lock()
+	defer rclient.refreshMutex.Unlock()
+
+	if rclient.refreshing[elementKey] {
+		return
+	}
+
+	if rclient.refreshing == nil {
+		rclient.refreshing = make(map[string]bool)
+	}
+
+	rclient.refreshing[elementKey] = true
+
+	go func() {
+		rclient.fetch(context.Background(), callURL, headers, cacheKey, element, elementKey, nil)
+
+		rclient.refreshMutex.Lock()
+		delete(rclient.refreshing, elementKey)
+		rclient.refreshMutex.Unlock()
+	}()
+}
+
+//Execute the call through the rate limit, bulkhead and circuit breaker of the pool
+func (rclient *rClient) call(ctx context.Context, method string, callURL string, body string, headers map[string]string) (*Response, error) {
+	//Pass the rate limit, the bulkhead and the circuit breaker of the pool
+	if error := rclient.acquire(ctx); error != nil {
+		return nil, error
+	}
+
+	defer rclient.release()
+
+	//Perform the call, retrying it if the pool has a retry policy
+	rcResponse, error := rclient.execute(ctx, method, callURL, body, headers)
+
+	rclient.done(rcResponse, error)
+
+	return rcResponse, error
+}
+
+//Wait for the turn of the call if the pool has a rate limit and for a free slot if the pool limits the concurrent
+//calls, failing fast while the circuit breaker of the pool is open. The slot must be freed with release.
+func (rclient *rClient) acquire(ctx context.Context) error {
+	if rclient.limiter != nil {
+		if error := rclient.limiter.take(ctx); error != nil {
+			return error
+		}
+	}
+
+	if rclient.bulkhead != nil {
+		if error := rclient.bulkhead.acquire(ctx); error != nil {
+			return error
+		}
+	}
+
+	if rclient.breaker != nil && !rclient.breaker.allow() {
+		rclient.release()
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+//Free the slot taken by acquire
+func (rclient *rClient) release() {
+	if rclient.bulkhead != nil {
+		rclient.bulkhead.release()
+	}
+}
+
+//Report the result of the call to the circuit breaker of the pool
+func (rclient *rClient) done(response *Response, error error) {
+	if rclient.breaker != nil {
+		rclient.breaker.done(response, error)
+	}
+}
+
+//Send the request to the API and read the whole response
+func (rclient *rClient) send(ctx context.Context, method string, callURL string, body string, headers map[string]string) (*Response, error) {
 	var request *http.Request
 	var error error
 
-	//Create the request to the API
-	if method == http.MethodPost || method == http.MethodPut {
-		request, error = http.NewRequest(method, callURL, bytes.NewBuffer([]byte(body)))
+	//Create the request to the API, sending the body whenever there is one
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || body != "" {
+		request, error = http.NewRequestWithContext(ctx, method, callURL, bytes.NewBuffer([]byte(body)))
 
 	} else {
-		request, error = http.NewRequest(method, callURL, nil)
+		request, error = http.NewRequestWithContext(ctx, method, callURL, nil)
 	}
 
 	//Checks for errors in the connection
@@ -309,7 +801,8 @@ func performRequest(method string, callURL string, body string, headers map[stri
 	}
 
 	if error != nil && !isNotFollowRedirectError {
-		rcResponse = &Response{"", 0, nil, false, false}
+		error = requestError(ctx, rclient, callURL, error)
+		rcResponse = &Response{}
 	}
 
 	var byteBody []byte
@@ -320,7 +813,8 @@ func performRequest(method string, callURL string, body string, headers map[stri
 			byteBody, error = ioutil.ReadAll(response.Body)
 
 			if error != nil {
-				rcResponse = &Response{"", response.StatusCode, nil, false, false}
+				error = requestError(ctx, rclient, callURL, error)
+				rcResponse = &Response{Code: response.StatusCode}
 			}
 		} else {
 			byteBody = []byte("")
@@ -328,23 +822,34 @@ func performRequest(method string, callURL string, body string, headers map[stri
 	}
 
 	if rcResponse == nil {
-		rcResponse = &Response{string(byteBody), response.StatusCode, response.Header, false, false}
+		rcResponse = &Response{Body: string(byteBody), Code: response.StatusCode, Headers: response.Header}
 	}
 
-	if withCache {
-		//Chek if we got 200OK
-		if rcResponse.Code == http.StatusOK {
-			setResponseInCache(rclient, rcResponse, callURL)
+	return rcResponse, error
+}
 
-		} else {
-			//If we got some error and the state option is configured, return the last good cached response
-			if rclient.stale && cachedResponse != nil {
-				return cachedResponse, nil
-			}
-		}
+//Return the url to call, adding the base url of the pool if it is needed
+func (rclient *rClient) getURL(callURL string) string {
+	if rclient.baseURL != "" && !strings.Contains(callURL, rclient.baseURL) {
+		return rclient.baseURL + callURL
 	}
 
-	return rcResponse, error
+	return callURL
+}
+
+//Distinguish the cancellation of the caller context from the pool timeout
+func requestError(ctx context.Context, rclient *rClient, callURL string, err error) error {
+	//The caller context was cancelled or its deadline was exceeded
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	//The pool timeout was exceeded
+	if netError, ok := err.(net.Error); ok && netError.Timeout() && rclient.timeout > 0 {
+		return &TimeoutError{URL: callURL, PoolTimeout: rclient.timeout}
+	}
+
+	return err
 }
 
 func defaultTransport() *http.Transport {
@@ -358,7 +863,7 @@ func defaultTransport() *http.Transport {
 }
 
 //InitDefaultPool initialize the rest client with the default settings
-func initDefaultPool() *rClient {
+func (c *Client) initDefaultPool() *rClient {
 	//Create a transport for the connection
 	transport := defaultTransport()
 
@@ -374,28 +879,23 @@ func initDefaultPool() *rClient {
 	//Creates the client-cache struct
 	rclient := new(rClient)
 	rclient.client = client
+	rclient.config = PoolConfig{MaxIdleConnsPerHost: DEFAULT_MAX_IDLE_CONNECTIONS_PER_HOST}
 
-	pools["default"] = rclient
-
-	return rclient
-}
+	c.poolMutex.Lock()
+	defer c.poolMutex.Unlock()
 
-//Return the http client based on the URL to call
-func getPool(callURL string) *rClient {
-	//If we found a pool, return it
-	for pattern, pool := range pools {
-		if regexp.MustCompile(pattern).MatchString(callURL) {
-			return pool
-		}
+	//Other call could have created it while the pools were unlocked
+	if pool := c.pools[defaultPoolName]; pool != nil {
+		return pool
 	}
 
-	//create a default pool
-	pool := pools["default"]
-	if pool == nil {
-		pool = initDefaultPool()
+	if c.pools == nil {
+		c.pools = make(map[string]*rClient)
 	}
 
-	return pool
+	c.pools[defaultPoolName] = rclient
+
+	return rclient
 }
 
 //SetHeaders set the headers to the request
@@ -454,33 +954,31 @@ func sameHeaders(h1 map[string]string, h2 map[string]string) bool {
 	return true
 }
 
-//Return the max age from the header
-func getMaxAge(cacheControlValues []string) (int, error) {
-	for _, value := range cacheControlValues {
-		if strings.Contains(strings.ToLower(value), "max-age") {
-			return strconv.Atoi(strings.Split(cacheControlValues[0], "=")[1])
-		}
+//Return the element saved in the cache for the key and the request headers, with its own key
+func getCacheElement(rclient *rClient, key string, headers map[string]string) (*cacheElement, string) {
+	//Chechs if it was previously saved
+	cElement := rclient.getElement(key)
+
+	//The response varies with the request headers, so we look for the variant of the headers sent
+	if cElement != nil && cElement.Vary != nil {
+		key = getVaryKey(key, cElement.Vary, headers)
+		cElement = rclient.getElement(key)
 	}
 
-	return 0, nil
+	return cElement, key
 }
 
 //Chechs if have to go to the cache for the element
-func getResponseFromCache(rclient *rClient, callURL string) *Response {
-	//Chechs if it was previously saved
-	if rclient.cache.Contains(callURL) {
-		//Get the element from the cache
-		element, _ := rclient.cache.Get(callURL)
-		cacheElement := element.(*cacheElement)
-
+func getResponseFromCache(rclient *rClient, cacheElement *cacheElement) *Response {
+	if cacheElement != nil {
 		//If it is still valid, return the content from the cache
 		if time.Now().Before(cacheElement.Expires) {
-			return &Response{cacheElement.Content, 200, cacheElement.Headers, true, false}
+			return &Response{Body: cacheElement.Content, Code: cacheElement.code(), Headers: cacheElement.Headers, CachedContent: true}
 		}
 
-		//Save the expired response for staled calls
-		if rclient.stale {
-			return &Response{cacheElement.Content, 200, cacheElement.Headers, true, true}
+		//Save the expired response for staled calls, unless the API asked to revalidate it
+		if rclient.stale && !cacheElement.MustRevalidate {
+			return &Response{Body: cacheElement.Content, Code: cacheElement.code(), Headers: cacheElement.Headers, CachedContent: true, Staled: true}
 		}
 	}
 
@@ -488,28 +986,125 @@ func getResponseFromCache(rclient *rClient, callURL string) *Response {
 }
 
 //Save the response to the cache
-func setResponseInCache(rclient *rClient, response *Response, callURL string) {
-	//Checks the cache control header
-	cacheControl := response.Headers["Cache-Control"]
-
-	if cacheControl != nil {
-		//Check the max age value
-		cacheControlValue, _ := getMaxAge(cacheControl)
-
-		//Checks if we have to cache or not
-		if cacheControlValue > 0 {
-			//Create elemento to store the cache values
-			cElement := new(cacheElement)
-			cElement.Content = response.Body
-			cElement.Headers = response.Headers
-			cElement.Expires = time.Now().Add(time.Second * time.Duration(cacheControlValue))
-
-			//Save the data in the cache
-			rclient.cache.Add(callURL, cElement)
+func setResponseInCache(rclient *rClient, response *Response, key string, headers map[string]string) {
+	expires, store, mustRevalidate := getPolicyFreshness(response.Headers, rclient.cachePolicy)
+
+	//The other status codes are cached during the time of the pool, unless the API forbids storing them
+	if response.Code != http.StatusOK {
+		expires = time.Now().Add(rclient.statusTTL[response.Code])
+		store = !parseCacheControl(response.Headers["Cache-Control"]).noStore || (rclient.cachePolicy != nil && rclient.cachePolicy.IgnoreServerHeaders)
+		mustRevalidate = false
+	}
+
+	//A response that varies with any request header can't be reused
+	vary, varyAll := getHeaderNames(response.Headers["Vary"])
+
+	if store && !varyAll {
+		//Create elemento to store the cache values
+		cElement := new(cacheElement)
+		cElement.Content = response.Body
+		cElement.Headers = response.Headers
+		cElement.Code = response.Code
+		cElement.Expires = expires
+		cElement.MustRevalidate = mustRevalidate
+		cElement.StaleUntil = expires.Add(rclient.getStaleWhileRevalidate(response.Headers))
+
+		//Only the 200 responses are revalidated, the other status codes are requested again once they expire
+		if response.Code == http.StatusOK {
+			cElement.ETag = http.Header(response.Headers).Get("ETag")
+			cElement.LastModified = http.Header(response.Headers).Get("Last-Modified")
 		}
+
+		//Save an element with the Vary names in the key, and the response in the key of its variant
+		if vary != nil {
+			rclient.setElement(key, &cacheElement{Vary: vary, StaleUntil: cElement.StaleUntil})
+			key = getVaryKey(key, vary, headers)
+		}
+
+		//Save the data in the cache
+		rclient.setElement(key, cElement)
 	}
 }
 
+//Return the status code of the cached response
+func (element *cacheElement) code() int {
+	if element.Code == 0 {
+		return http.StatusOK
+	}
+
+	return element.Code
+}
+
+//Add the conditional headers to revalidate the expired element, unless the caller sent its own ones
+func revalidationHeaders(element *cacheElement, headers map[string]string) (map[string]string, bool) {
+	if element == nil || (element.ETag == "" && element.LastModified == "") {
+		return headers, false
+	}
+
+	for key := range headers {
+		if http.CanonicalHeaderKey(key) == "If-None-Match" || http.CanonicalHeaderKey(key) == "If-Modified-Since" {
+			return headers, false
+		}
+	}
+
+	//Copy the headers, so the caller map is not changed
+	conditionalHeaders := make(map[string]string, len(headers)+2)
+	for key, value := range headers {
+		conditionalHeaders[key] = value
+	}
+
+	if element.ETag != "" {
+		conditionalHeaders["If-None-Match"] = element.ETag
+	}
+
+	if element.LastModified != "" {
+		conditionalHeaders["If-Modified-Since"] = element.LastModified
+	}
+
+	return conditionalHeaders, true
+}
+
+//Refresh the cached element with the headers of the not modified response and return its content
+func refreshCacheElement(rclient *rClient, element *cacheElement, response *Response, key string) *Response {
+	//Update the stored headers with the ones sent in the not modified response
+	headers := make(map[string][]string, len(element.Headers))
+	for key, value := range element.Headers {
+		headers[key] = value
+	}
+	for key, value := range response.Headers {
+		headers[key] = value
+	}
+
+	//Create a new element, because the old one can be in use by other calls
+	cElement := new(cacheElement)
+	cElement.Content = element.Content
+	cElement.Headers = headers
+	cElement.ETag = http.Header(headers).Get("ETag")
+	cElement.LastModified = http.Header(headers).Get("Last-Modified")
+
+	var store bool
+	cElement.Expires, store, cElement.MustRevalidate = getPolicyFreshness(headers, rclient.cachePolicy)
+	cElement.StaleUntil = cElement.Expires.Add(rclient.getStaleWhileRevalidate(headers))
+
+	//The API can ask to stop storing the response
+	if store {
+		rclient.setElement(key, cElement)
+	} else {
+		rclient.cache.Delete(key)
+	}
+
+	return &Response{Body: cElement.Content, Code: 200, Headers: headers, CachedContent: true, Attempts: response.Attempts}
+}
+
+//Return the time an expired response can be returned while it is refreshed
+func (rclient *rClient) getStaleWhileRevalidate(headers map[string][]string) time.Duration {
+	if rclient.staleWhileRevalidate > 0 {
+		return rclient.staleWhileRevalidate
+	}
+
+	return time.Duration(parseCacheControl(headers["Cache-Control"]).staleWhileRevalidate) * time.Second
+}
+
 func getHeadersMap(headers []Header) map[string]string {
 	headersMap := make(map[string]string)
 
@@ -521,9 +1116,12 @@ func getHeadersMap(headers []Header) map[string]string {
 }
 
 //search for the url in the mocks array
-func searchMockCall(method string, callURL string, headers map[string]string, body string) *Response {
+func (c *Client) searchMockCall(method string, callURL string, headers map[string]string, body string) *Response {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	//Check every mock URL
-	for _, mock := range mocks {
+	for _, mock := range c.mocks {
 
 		var mached bool
 		if mock.Regexp {
@@ -538,10 +1136,3 @@ func searchMockCall(method string, callURL string, headers map[string]string, bo
 
 	return nil
 }
-
-//Init the Mock files
-func init() {
-	if os.Getenv("GO_ENVIRONMENT") != "production" {
-		useMock = true
-	}
-}
@@ -6,9 +6,12 @@
 package restclient
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -114,19 +117,19 @@ func TestCustomPool(t *testing.T) {
 
 	AddCustomPool("/categories/.*", categoriesPool)
 
-	client := getPool("http://items.mercadolibre.com/items/MLA1")
+	client := defaultClient.getPool("http://items.mercadolibre.com/items/MLA1")
 
 	if client.baseURL != itemsPool.BaseURL {
 		t.Fatalf("The content was not as expected, expected: %s, got: %s", itemsPool.BaseURL, client.baseURL)
 	}
 
-	client = getPool("http://users.mercadolibre.com/users/1")
+	client = defaultClient.getPool("http://users.mercadolibre.com/users/1")
 
 	if client.baseURL != usersPool.BaseURL {
 		t.Fatalf("The content was not as expected, expected: %s, got: %s", itemsPool.BaseURL, client.baseURL)
 	}
 
-	client = getPool("http://categories.mercadolibre.com/categories/MLA3530")
+	client = defaultClient.getPool("http://categories.mercadolibre.com/categories/MLA3530")
 
 	if client.baseURL != categoriesPool.BaseURL {
 		t.Fatalf("The content was not as expected, expected: %s, got: %s", itemsPool.BaseURL, client.baseURL)
@@ -654,6 +657,425 @@ func TestSeveralGETMocks(t *testing.T) {
 
 }
 
+func TestZeroValueClient(t *testing.T) {
+
+	//Use a client without creating it with NewClient
+	var client Client
+
+	response, err := client.Get("http://localhost:8080/testing")
+
+	//Checks if there was an error
+	if err != nil || response.Body != "{\"id\":\"MLA\"}" {
+		t.Fatal("The zero value client should do the call", err)
+	}
+
+	//Checks that pools can be added
+	if err := client.AddCustomPool("/items/.*", &PoolConfig{}); err != nil || client.Route("/items/1") != "/items/.*" {
+		t.Fatal("The pool should be added", err)
+	}
+
+	//The end
+	fmt.Println("End TestZeroValueClient")
+
+}
+
+func TestClientIsolation(t *testing.T) {
+
+	//Create two clients with the same pool pattern
+	itemsClient := NewClient()
+	usersClient := NewClient()
+
+	itemsPool := new(PoolConfig)
+	itemsPool.BaseURL = "http://items.mercadolibre.com"
+	itemsClient.AddCustomPool("/resources/.*", itemsPool)
+
+	usersPool := new(PoolConfig)
+	usersPool.BaseURL = "http://users.mercadolibre.com"
+	usersClient.AddCustomPool("/resources/.*", usersPool)
+
+	//Checks that every client keeps its own pool
+	if pool := itemsClient.getPool("/resources/1"); pool.baseURL != itemsPool.BaseURL {
+		t.Fatalf("The content was not as expected, expected: %s, got: %s", itemsPool.BaseURL, pool.baseURL)
+	}
+
+	if pool := usersClient.getPool("/resources/1"); pool.baseURL != usersPool.BaseURL {
+		t.Fatalf("The content was not as expected, expected: %s, got: %s", usersPool.BaseURL, pool.baseURL)
+	}
+
+	//Add a mock only to one of the clients
+	itemsClient.AddMock("http://isolated.com", "GET", "", Response{Body: "{\"id\":\"Items\"}", Code: 200})
+
+	response, err := itemsClient.Get("http://isolated.com")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the content of the body is the mocked one
+	if response.Body != "{\"id\":\"Items\"}" {
+		t.Fatal("The content was not as expected", "{\"id\":\"Items\"}", response.Body)
+	}
+
+	//The other client and the package functions must not see the mock
+	if usersClient.searchMockCall("GET", "http://isolated.com", map[string]string{}, "") != nil {
+		t.Fatal("The mock was shared between clients")
+	}
+
+	if defaultClient.searchMockCall("GET", "http://isolated.com", map[string]string{}, "") != nil {
+		t.Fatal("The mock was shared with the default client")
+	}
+
+	//The end
+	fmt.Println("End TestClientIsolation")
+
+}
+
+func TestGetCtx(t *testing.T) {
+
+	//Do the GET call with a context that is not cancelled
+	response, err := GetCtx(context.Background(), "http://localhost:8080/testing")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the content of the body is as expected
+	if response.Code != 200 || response.Body != "{\"id\":\"MLA\"}" {
+		t.Fatal("The content was not as expected", "{\"id\":\"MLA\"}", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetCtx")
+
+}
+
+func TestGetCtxCancelled(t *testing.T) {
+
+	//Create a context with a deadline lower than the server delay (100ms)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	//Do the GET call
+	_, err := GetCtx(ctx, "http://localhost:8080/testing")
+
+	//Checks that the error is the context one
+	if err != context.DeadlineExceeded {
+		t.Fatal("We should had got a context deadline error", err)
+	}
+
+	//Cancel the context before the server answers
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	//Do the POST call
+	_, err = PostCtx(ctx, "http://localhost:8080/slow", "{\"id\":\"MLA\"}")
+
+	//Checks that the error is the context one
+	if err != context.Canceled {
+		t.Fatal("We should had got a context cancelled error", err)
+	}
+
+	//The end
+	fmt.Println("End TestGetCtxCancelled")
+
+}
+
+func TestGetCtxWithPoolTimeout(t *testing.T) {
+
+	//Create a client with a pool timeout lower than the server delay
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.Timeout = 10
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call with a context without deadline
+	_, err := client.GetCtx(context.Background(), "http://localhost:8080/testing")
+
+	//Checks that the error is the pool timeout
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatal("We should had got a pool timeout error", err)
+	}
+
+	//The end
+	fmt.Println("End TestGetCtxWithPoolTimeout")
+
+}
+
+func TestPatchDefault(t *testing.T) {
+
+	//Do the PATCH call
+	response, err := Patch("http://localhost:8080/echo", "{\"id\":\"MLA\"}")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the response was 200OK
+	if response.Code != 200 {
+		t.Fatal("There was not 200OK", "200", response.Code)
+	}
+
+	//Checks if the content of the body is as expected
+	if response.Body != "PATCH --> {\"id\":\"MLA\"}" {
+		t.Fatal("The content was not as expected", "PATCH --> {\"id\":\"MLA\"}", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestPatchDefault")
+
+}
+
+func TestDoWithBody(t *testing.T) {
+
+	//Do a DELETE call with body
+	response, err := Do(http.MethodDelete, "http://localhost:8080/echo", "{\"id\":\"MLA\"}")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the content of the body is as expected
+	if response.Body != "DELETE --> {\"id\":\"MLA\"}" {
+		t.Fatal("The content was not as expected", "DELETE --> {\"id\":\"MLA\"}", response.Body)
+	}
+
+	//Do a custom method call without body
+	response, err = Do("PURGE", "http://localhost:8080/echo", "")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the content of the body is as expected
+	if response.Body != "PURGE --> " {
+		t.Fatal("The content was not as expected", "PURGE --> ", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestDoWithBody")
+
+}
+
+func TestDoWithMock(t *testing.T) {
+
+	//Create a client with a mock for a PATCH call
+	client := NewClient()
+	client.AddMock("http://fer.com/items/1", http.MethodPatch, "{\"id\":\"FER\"}", Response{Body: "{\"id\":\"FER\"}", Code: 200})
+
+	//Do the call using the generic entry point
+	response, err := client.Do(http.MethodPatch, "http://fer.com/items/1", "{\"id\":\"FER\"}")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the content of the body is the mocked one
+	if response.Body != "{\"id\":\"FER\"}" {
+		t.Fatal("The content was not as expected", "{\"id\":\"FER\"}", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestDoWithMock")
+
+}
+
+func TestGetWithCacheRevalidation(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client with cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	for _, validator := range []string{"etag", "date"} {
+
+		resource := "http://localhost:8080/validators?validator=" + validator
+
+		//Do the GET call, the response is valid for 1 second
+		response, _ := client.Get(resource)
+
+		//Checks if the content of the body is as expected and was not from the cache
+		if response.Body != "{\"id\":\"MLA\"}" || response.CachedContent == true {
+			t.Fatal("The content was not as expected", "{\"id\":\"MLA\"}", response.Body)
+		}
+
+		//Sleep to expire the cached response
+		time.Sleep(1100 * time.Millisecond)
+
+		//Do the GET call, the API answers 304 and the cached content is returned
+		revalidatedResponse, err := client.Get(resource)
+
+		if err != nil || revalidatedResponse.Code != 200 || revalidatedResponse.Body != "{\"id\":\"MLA\"}" || revalidatedResponse.CachedContent == false {
+			t.Fatal("The content was not revalidated", validator, revalidatedResponse.Code, revalidatedResponse.Body, err)
+		}
+
+		//Do the GET call, the refreshed element is valid for 10 seconds
+		cachedResponse, _ := client.Get(resource)
+
+		if cachedResponse.CachedContent == false || cachedResponse.Attempts != 0 {
+			t.Fatal("The refreshed content was not cached", validator)
+		}
+
+		//Checks the calls received by the API
+		flakyMutex.Lock()
+		calls := flakyCalls["validators-"+validator]
+		notModified := flakyCalls["validators-"+validator+"-304"]
+		flakyMutex.Unlock()
+
+		if calls != 2 || notModified != 1 {
+			t.Fatal("The calls were not as expected", validator, calls, notModified)
+		}
+	}
+
+	//The end
+	fmt.Println("End TestGetWithCacheRevalidation")
+
+}
+
+func TestGetWithStaleWhileRevalidate(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client with cache
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the response is fresh for 1 second and can be returned stale for 10 seconds more
+	response, _ := client.Get("http://localhost:8080/counter?id=swr&directives=max-age=1,stale-while-revalidate=10")
+
+	if response.Body != "{\"call\":1}" || response.CachedContent {
+		t.Fatal("The content was not as expected", response.Body)
+	}
+
+	//Sleep to expire the cached response
+	time.Sleep(1100 * time.Millisecond)
+
+	//Do several GET calls, all of them get the stale response immediately
+	for i := 0; i < 5; i++ {
+		response, _ = client.Get("http://localhost:8080/counter?id=swr&directives=max-age=1,stale-while-revalidate=10")
+
+		if response.Body != "{\"call\":1}" || !response.Staled || !response.CachedContent {
+			t.Fatal("The stale content was not returned", response.Body)
+		}
+	}
+
+	//Wait for the background refresh
+	time.Sleep(200 * time.Millisecond)
+
+	//Do the GET call, getting the refreshed response
+	response, _ = client.Get("http://localhost:8080/counter?id=swr&directives=max-age=1,stale-while-revalidate=10")
+
+	if response.Body != "{\"call\":2}" || response.Staled || !response.CachedContent {
+		t.Fatal("The refreshed content was not returned", response.Body)
+	}
+
+	//Checks that only one refresh was done
+	flakyMutex.Lock()
+	calls := flakyCalls["counter-swr"]
+	flakyMutex.Unlock()
+
+	if calls != 2 {
+		t.Fatal("Only one refresh should be done", calls)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithStaleWhileRevalidate")
+
+}
+
+func TestGetWithPoolStaleWhileRevalidate(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that returns expired responses for 10 seconds while they are refreshed
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.StaleWhileRevalidate = 10000
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the response is fresh for 1 second
+	client.Get("http://localhost:8080/counter?id=poolswr&directives=max-age=1")
+
+	//Sleep to expire the cached response
+	time.Sleep(1100 * time.Millisecond)
+
+	//Do the GET call, getting the stale response
+	response, _ := client.Get("http://localhost:8080/counter?id=poolswr&directives=max-age=1")
+
+	if response.Body != "{\"call\":1}" || !response.Staled {
+		t.Fatal("The stale content was not returned", response.Body)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithPoolStaleWhileRevalidate")
+
+}
+
+func TestGetWithCachedStatusCodes(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that caches the 404 responses for 1 second
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+	config.CacheStatusCodes = map[int]time.Duration{http.StatusNotFound: 1000}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the response is not found
+	client.Get("http://localhost:8080/counter?id=notfound&code=404")
+
+	//Do the GET call again, getting the cached response with its code
+	response, _ := client.Get("http://localhost:8080/counter?id=notfound&code=404")
+
+	if !response.CachedContent || response.Code != http.StatusNotFound || response.Body != "{\"call\":1}" {
+		t.Fatal("The not found response was not cached", response.Code, response.Body)
+	}
+
+	//Checks that the other status codes are not cached
+	client.Get("http://localhost:8080/counter?id=gone&code=410")
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=gone&code=410"); response.CachedContent {
+		t.Fatal("The gone response should not be cached")
+	}
+
+	//Sleep to expire the cached response
+	time.Sleep(1100 * time.Millisecond)
+
+	if response, _ := client.Get("http://localhost:8080/counter?id=notfound&code=404"); response.CachedContent {
+		t.Fatal("The not found response should be expired")
+	}
+
+	//The end
+	fmt.Println("End TestGetWithCachedStatusCodes")
+
+}
+
 ///// Utils /////
 
 //Sleep for 100ms
@@ -666,6 +1088,13 @@ func restAPI() {
 	//Create the webserver
 	http.Handle("/testing", http.HandlerFunc(processRequestDefault))
 	http.Handle("/cache", http.HandlerFunc(processRequestCache))
+	http.Handle("/slow", http.HandlerFunc(processRequestSlow))
+	http.Handle("/echo", http.HandlerFunc(processRequestEcho))
+	http.Handle("/flaky", http.HandlerFunc(processRequestFlaky))
+	http.Handle("/hedge", http.HandlerFunc(processRequestHedge))
+	http.Handle("/validators", http.HandlerFunc(processRequestValidators))
+	http.Handle("/vary", http.HandlerFunc(processRequestVary))
+	http.Handle("/counter", http.HandlerFunc(processRequestCounter))
 
 	err := http.ListenAndServe("0.0.0.0:8080", nil)
 	if err != nil {
@@ -766,3 +1195,89 @@ func processRequestDefault(w http.ResponseWriter, req *http.Request) {
 	}
 
 }
+
+//processRequestSlow answers every request after 100ms
+func processRequestSlow(w http.ResponseWriter, req *http.Request) {
+
+	//Wait 100ms
+	sleep()
+
+	w.WriteHeader(200)
+	w.Write([]byte("{\"id\":\"MLA\"}"))
+
+}
+
+//processRequestEcho returns the method and the body of the request
+func processRequestEcho(w http.ResponseWriter, req *http.Request) {
+
+	//Reads the body content
+	body, _ := ioutil.ReadAll(req.Body)
+
+	w.WriteHeader(200)
+	w.Write([]byte(req.Method + " --> " + string(body)))
+
+}
+
+//processRequestValidators answers 304 when the request has the expected validator
+func processRequestValidators(w http.ResponseWriter, req *http.Request) {
+
+	validator := req.URL.Query().Get("validator")
+
+	flakyMutex.Lock()
+	flakyCalls["validators-"+validator]++
+	flakyMutex.Unlock()
+
+	lastModified := "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	//Checks the validator sent
+	if (validator == "etag" && req.Header.Get("If-None-Match") == "\"v1\"") || (validator == "date" && req.Header.Get("If-Modified-Since") == lastModified) {
+		flakyMutex.Lock()
+		flakyCalls["validators-"+validator+"-304"]++
+		flakyMutex.Unlock()
+
+		w.Header().Add("Cache-Control", "max-age=10")
+		w.WriteHeader(304)
+		return
+	}
+
+	if validator == "etag" {
+		w.Header().Add("ETag", "\"v1\"")
+	} else {
+		w.Header().Add("Last-Modified", lastModified)
+	}
+
+	w.Header().Add("Cache-Control", "max-age=1")
+	w.WriteHeader(200)
+	w.Write([]byte("{\"id\":\"MLA\"}"))
+
+}
+
+//processRequestCounter returns the number of calls of the id after 50ms, with the Cache-Control sent in the query
+func processRequestCounter(w http.ResponseWriter, req *http.Request) {
+
+	id := req.URL.Query().Get("id")
+
+	flakyMutex.Lock()
+	flakyCalls["counter-"+id]++
+	calls := flakyCalls["counter-"+id]
+	flakyMutex.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if directives := req.URL.Query().Get("directives"); directives != "" {
+		w.Header().Add("Cache-Control", directives)
+	}
+
+	if location := req.URL.Query().Get("location"); location != "" {
+		w.Header().Add("Location", location)
+	}
+
+	code := 200
+	if status := req.URL.Query().Get("code"); status != "" {
+		code, _ = strconv.Atoi(status)
+	}
+
+	w.WriteHeader(code)
+	w.Write([]byte(fmt.Sprintf("{\"call\":%d}", calls)))
+
+}
@@ -0,0 +1,155 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+//RetryErrorClass indicates the kind of errors that can be retried
+type RetryErrorClass int
+
+const (
+	//RetryConnectionErrors retries the calls that couldn't connect or lost the connection
+	RetryConnectionErrors RetryErrorClass = 1 << iota
+	//RetryTimeoutErrors retries the calls that exceeded the pool Timeout
+	RetryTimeoutErrors
+)
+
+//RetryPolicy is used to define how the failed calls of a pool are retried
+type RetryPolicy struct {
+	//Max number of attempts, including the first call
+	MaxAttempts int
+	//Wait before the first retry, doubled on every attempt (in milliseconds)
+	BaseBackoff time.Duration
+	//Max wait between attempts (in milliseconds)
+	MaxBackoff time.Duration
+	//Fraction (0 to 1) of every wait that is randomly removed
+	Jitter float64
+	//Status codes to retry, if nil 502, 503 and 504 are retried
+	RetryStatusCodes []int
+	//Errors to retry, if 0 the connection and timeout errors are retried
+	RetryErrors RetryErrorClass
+	//Only retry the idempotent methods (GET, HEAD, OPTIONS, TRACE, PUT and DELETE)
+	OnlyIdempotent bool
+}
+
+var defaultRetryStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+//Perform the call retrying it according to the retry policy of the pool
+func (rclient *rClient) execute(ctx context.Context, method string, callURL string, body string, headers map[string]string) (*Response, error) {
+	policy := rclient.retry
+
+	for attempt := 1; ; attempt++ {
+		response, error := rclient.hedgedSend(ctx, method, callURL, body, headers)
+
+		if response != nil {
+			response.Attempts = attempt
+		}
+
+		//Return the response if there are no more attempts or the result can't be retried
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.retryable(method, response, error) {
+			return response, error
+		}
+
+		//Wait before the next attempt, unless the caller context is done
+		timer := time.NewTimer(policy.backoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, ctx.Err()
+		case <-timer.C:
+		}
+
+		//Every retry takes a token of the rate limit of the pool, without quota the last result is returned
+		if rclient.limiter != nil && rclient.limiter.take(ctx) != nil {
+			return response, error
+		}
+	}
+}
+
+//Check if the result of the call can be retried
+func (policy *RetryPolicy) retryable(method string, response *Response, err error) bool {
+	if policy.OnlyIdempotent && !idempotent(method) {
+		return false
+	}
+
+	if err != nil {
+		classes := policy.RetryErrors
+		if classes == 0 {
+			classes = RetryConnectionErrors | RetryTimeoutErrors
+		}
+
+		return classes&errorClass(err) != 0
+	}
+
+	codes := policy.RetryStatusCodes
+	if codes == nil {
+		codes = defaultRetryStatusCodes
+	}
+
+	for _, code := range codes {
+		if response.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Return the wait before the next attempt
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	wait := policy.BaseBackoff * time.Millisecond
+
+	//Double the wait on every attempt until the max is reached, or until doubling it would overflow
+	for i := 1; i < attempt && wait <= math.MaxInt64/2 && (policy.MaxBackoff == 0 || wait < policy.MaxBackoff*time.Millisecond); i++ {
+		wait *= 2
+	}
+
+	if policy.MaxBackoff > 0 && wait > policy.MaxBackoff*time.Millisecond {
+		wait = policy.MaxBackoff * time.Millisecond
+	}
+
+	//Remove a random part of the wait, so the clients don't retry at the same time
+	if policy.Jitter > 0 {
+		wait -= time.Duration(rand.Float64() * policy.Jitter * float64(wait))
+	}
+
+	return wait
+}
+
+//Return the class of the error of a call
+func errorClass(err error) RetryErrorClass {
+	//The calls cancelled by the caller are never retried
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return 0
+	}
+
+	if _, ok := err.(*TimeoutError); ok {
+		return RetryTimeoutErrors
+	}
+
+	var opError *net.OpError
+	if errors.As(err, &opError) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return RetryConnectionErrors
+	}
+
+	return 0
+}
+
+//Check if the method is idempotent
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,155 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetWithRetry(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that retries the 503 responses
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.Retry = &RetryPolicy{MaxAttempts: 3, BaseBackoff: 1, MaxBackoff: 5, Jitter: 0.5}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call, the server fails twice before answering
+	response, err := client.Get("http://localhost:8080/flaky?id=retry&failures=2")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks if the response was 200OK after 3 attempts
+	if response.Code != 200 || response.Attempts != 3 {
+		t.Fatal("There was not 200OK after 3 attempts", response.Code, response.Attempts)
+	}
+
+	//Do the GET call, the server fails more times than the attempts
+	response, _ = client.Get("http://localhost:8080/flaky?id=exhausted&failures=5")
+
+	//Checks that we got the last failed response
+	if response.Code != 503 || response.Attempts != 3 {
+		t.Fatal("There was not 503 after 3 attempts", response.Code, response.Attempts)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithRetry")
+
+}
+
+func TestPostWithOnlyIdempotentRetry(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	//Create a client that only retries idempotent methods
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.Retry = &RetryPolicy{MaxAttempts: 3, OnlyIdempotent: true}
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the POST call
+	response, _ := client.Post("http://localhost:8080/flaky?id=post&failures=1", "{}")
+
+	//Checks that the call was not retried
+	if response.Code != 503 || response.Attempts != 1 {
+		t.Fatal("The POST should not be retried", response.Code, response.Attempts)
+	}
+
+	//The end
+	fmt.Println("End TestPostWithOnlyIdempotentRetry")
+
+}
+
+func TestGetWithRetryOnConnectionError(t *testing.T) {
+
+	//Create a client that retries the connection errors
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.Retry = &RetryPolicy{MaxAttempts: 2, RetryErrors: RetryConnectionErrors}
+
+	client.AddCustomPool("http://localhost:8081", config)
+
+	//Do the GET call to a port without server
+	response, err := client.Get("http://localhost:8081/testing")
+
+	//Checks that the call was retried
+	if err == nil || response.Attempts != 2 {
+		t.Fatal("The connection error should be retried", err, response.Attempts)
+	}
+
+	//The end
+	fmt.Println("End TestGetWithRetryOnConnectionError")
+
+}
+
+func TestRetryBackoff(t *testing.T) {
+
+	//Create a policy without max backoff
+	policy := &RetryPolicy{BaseBackoff: 1}
+
+	//Checks that the wait is doubled on every attempt
+	if wait := policy.backoff(3); wait != 4*time.Millisecond {
+		t.Fatal("The wait was not as expected", wait)
+	}
+
+	//Checks that the wait doesn't overflow after many attempts
+	if wait := policy.backoff(100); wait <= 0 {
+		t.Fatal("The wait should not overflow", wait)
+	}
+
+	//The end
+	fmt.Println("End TestRetryBackoff")
+
+}
+
+//Number of calls received by the flaky resource
+var flakyCalls = make(map[string]int)
+var flakyMutex = &sync.Mutex{}
+
+//Reset the counts of the calls, so the tests that check them can be run several times in the same process
+func resetFlakyCalls() {
+	flakyMutex.Lock()
+	defer flakyMutex.Unlock()
+
+	for id := range flakyCalls {
+		delete(flakyCalls, id)
+	}
+}
+
+//processRequestFlaky answers 503 until the number of failures of the id is reached
+func processRequestFlaky(w http.ResponseWriter, req *http.Request) {
+
+	id := req.URL.Query().Get("id")
+
+	var failures int
+	fmt.Sscan(req.URL.Query().Get("failures"), &failures)
+
+	flakyMutex.Lock()
+	flakyCalls[id]++
+	calls := flakyCalls[id]
+	flakyMutex.Unlock()
+
+	if calls <= failures {
+		w.WriteHeader(503)
+		w.Write([]byte("{\"error\":\"Service unavailable\"}"))
+		return
+	}
+
+	w.WriteHeader(200)
+	w.Write([]byte("{\"id\":\"MLA\"}"))
+
+}
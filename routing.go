@@ -0,0 +1,126 @@
+package restclient
+
+import (
+	"regexp"
+	"sort"
+)
+
+//Name of the pool used by the calls that don't match any pattern
+const defaultPoolName = "default"
+
+//Route of the calls whose url matches the pattern to its pool
+type poolRoute struct {
+	pattern    string
+	expression *regexp.Regexp
+	priority   int
+	//Registration order, used between the routes with the same priority
+	order int
+	pool  *rClient
+}
+
+//Route returns the pattern of the pool used by the calls to the url, or "default" if it doesn't match any pattern
+func Route(callURL string) string {
+	return defaultClient.Route(callURL)
+}
+
+//Route returns the pattern of the client pool used by the calls to the url, or "default" if it doesn't match any pattern
+func (c *Client) Route(callURL string) string {
+	c.poolMutex.RLock()
+	defer c.poolMutex.RUnlock()
+
+	if route := c.getRoute(callURL); route != nil {
+		return route.pattern
+	}
+
+	return defaultPoolName
+}
+
+//Return the http client based on the URL to call
+func (c *Client) getPool(callURL string) *rClient {
+	c.poolMutex.RLock()
+	route := c.getRoute(callURL)
+	pool := c.pools[defaultPoolName]
+	c.poolMutex.RUnlock()
+
+	//If we found a pool, return it
+	if route != nil {
+		return route.pool
+	}
+
+	//create a default pool
+	if pool == nil {
+		pool = c.initDefaultPool()
+	}
+
+	return pool
+}
+
+//Return the first route that matches the url, the routes are sorted by priority and registration order.
+//It is called with the pools locked.
+func (c *Client) getRoute(callURL string) *poolRoute {
+	for _, route := range c.routes {
+		if route.expression.MatchString(callURL) {
+			return route
+		}
+	}
+
+	return nil
+}
+
+//Save the pool of the pattern, replacing the previous pool of the same pattern but keeping its registration order.
+//It returns the replaced pool, if there was one, or ErrPoolNotFound if mustExist and there was not.
+func (c *Client) addPool(pattern string, expression *regexp.Regexp, priority int, rclient *rClient, mustExist bool) (*rClient, error) {
+	c.poolMutex.Lock()
+	defer c.poolMutex.Unlock()
+
+	//The pools of a zero value client are created with the first one
+	if c.pools == nil {
+		c.pools = make(map[string]*rClient)
+	}
+
+	previous := c.pools[pattern]
+	if previous == nil && mustExist {
+		return nil, ErrPoolNotFound
+	}
+
+	c.pools[pattern] = rclient
+
+	//The default pool is not matched against the urls
+	if expression == nil {
+		return previous, nil
+	}
+
+	route := c.findRoute(pattern)
+
+	if route == nil {
+		route = &poolRoute{pattern: pattern, order: c.routeCount}
+		c.routeCount++
+		c.routes = append(c.routes, route)
+	}
+
+	route.expression = expression
+	route.priority = priority
+	route.pool = rclient
+
+	//The higher priorities are evaluated first
+	sort.SliceStable(c.routes, func(i, j int) bool {
+		if c.routes[i].priority != c.routes[j].priority {
+			return c.routes[i].priority > c.routes[j].priority
+		}
+
+		return c.routes[i].order < c.routes[j].order
+	})
+
+	return previous, nil
+}
+
+//Return the route of the pattern, if it was added. It is called with the pools locked.
+func (c *Client) findRoute(pattern string) *poolRoute {
+	for _, route := range c.routes {
+		if route.pattern == pattern {
+			return route
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package restclient
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+
+	//Create a client with overlapping pools
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080", &PoolConfig{})
+	client.AddCustomPool("/testing.*", &PoolConfig{})
+
+	//Checks that the pools are evaluated in registration order
+	for i := 0; i < 10; i++ {
+		if pattern := client.Route("http://localhost:8080/testing"); pattern != "http://localhost:8080" {
+			t.Fatal("The first registered pool should be used", pattern)
+		}
+	}
+
+	//Checks that the priority is evaluated first
+	client.AddCustomPool("/testing.*", &PoolConfig{Priority: 1})
+
+	if pattern := client.Route("http://localhost:8080/testing"); pattern != "/testing.*" {
+		t.Fatal("The pool with priority should be used", pattern)
+	}
+
+	//Checks that the urls that don't match any pattern use the default pool, even if they contain "default"
+	client.AddCustomPool("default", &PoolConfig{})
+
+	if pattern := client.Route("http://other/default"); pattern != "default" || client.getPool("http://other/default") != client.pools["default"] {
+		t.Fatal("The default pool should be used", pattern)
+	}
+
+	//Checks that the invalid patterns return an error
+	if err := client.AddCustomPool("[a-", &PoolConfig{}); err == nil {
+		t.Fatal("We should had got an error")
+	}
+
+	//The end
+	fmt.Println("End TestRoute")
+
+}
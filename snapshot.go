@@ -0,0 +1,152 @@
+package restclient
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//ErrInvalidSnapshot is returned when the file is not a cache snapshot of a supported version
+var ErrInvalidSnapshot = errors.New("Invalid cache snapshot")
+
+//ErrPoolWithoutCache is returned when the pool of the pattern doesn't exist or it doesn't have cache
+var ErrPoolWithoutCache = errors.New("Pool without cache")
+
+//Header of the snapshot files, followed by the version of the format
+const snapshotMagic = "RCSNAP"
+const snapshotVersion = 1
+
+//SaveCache writes the cached responses of the pool of the pattern to the file
+func SaveCache(pattern string, path string) error {
+	return defaultClient.SaveCache(pattern, path)
+}
+
+//LoadCache adds the responses of the file that can still be used (fresh, or kept to be revalidated or returned as
+//staled) to the cache of the pool of the pattern, returning how many were loaded
+func LoadCache(pattern string, path string) (int, error) {
+	return defaultClient.LoadCache(pattern, path)
+}
+
+//SaveCache writes the cached responses of the client pool of the pattern to the file
+func (c *Client) SaveCache(pattern string, path string) error {
+	rclient := c.getPoolByPattern(pattern)
+	if rclient == nil || rclient.cache == nil {
+		return ErrPoolWithoutCache
+	}
+
+	//Write a temporary file and rename it, so a previous snapshot is never left partial
+	file, error := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if error != nil {
+		return error
+	}
+
+	defer os.Remove(file.Name())
+
+	writer := bufio.NewWriter(file)
+	writer.WriteString(snapshotMagic)
+	writer.WriteByte(snapshotVersion)
+
+	for _, key := range rclient.cache.Keys() {
+		if value, ok := rclient.cache.Get(key); ok {
+			writer.Write(encodeSnapshotEntry(key, value))
+		}
+	}
+
+	error = writer.Flush()
+
+	if closeError := file.Close(); error == nil {
+		error = closeError
+	}
+
+	if error != nil {
+		return error
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+//LoadCache adds the responses of the file that can still be used (fresh, or kept to be revalidated or returned as staled)
+//to the cache of the client pool of the pattern, returning how many were loaded.
+//If the file is truncated or corrupt, the entries before the damage are loaded.
+func (c *Client) LoadCache(pattern string, path string) (int, error) {
+	rclient := c.getPoolByPattern(pattern)
+	if rclient == nil || rclient.cache == nil {
+		return 0, ErrPoolWithoutCache
+	}
+
+	file, error := os.Open(path)
+	if error != nil {
+		return 0, error
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, error := io.ReadFull(reader, header); error != nil || string(header[:len(snapshotMagic)]) != snapshotMagic || header[len(snapshotMagic)] != snapshotVersion {
+		return 0, ErrInvalidSnapshot
+	}
+
+	loaded := 0
+
+	for {
+		key, value, ok := readSnapshotEntry(reader)
+		if !ok {
+			return loaded, nil
+		}
+
+		//Save the element following the expiration rules of the pool
+		element := decodeElement(value)
+		if element == nil {
+			continue
+		}
+
+		if rclient.setElement(key, element) {
+			loaded++
+		}
+	}
+}
+
+//Encode the entry as: length of the data, checksum of the data and the data (key length, key and value)
+func encodeSnapshotEntry(key string, value []byte) []byte {
+	data := make([]byte, 12, 12+len(key)+len(value))
+
+	binary.BigEndian.PutUint32(data[8:12], uint32(len(key)))
+
+	data = append(data, key...)
+	data = append(data, value...)
+
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(data)-8))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(data[8:]))
+
+	return data
+}
+
+//Read the next entry, it fails at the end of the file and when the entry is partial or corrupt
+func readSnapshotEntry(reader io.Reader) (string, []byte, bool) {
+	header := make([]byte, 8)
+	if _, error := io.ReadFull(reader, header); error != nil {
+		return "", nil, false
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+
+	//A corrupt length could be huge, so the data is read without allocating it first
+	data, error := ioutil.ReadAll(io.LimitReader(reader, int64(length)))
+	if error != nil || uint32(len(data)) != length || length < 4 || crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+		return "", nil, false
+	}
+
+	keyLength := binary.BigEndian.Uint32(data[0:4])
+	if uint64(keyLength) > uint64(len(data)-4) {
+		return "", nil, false
+	}
+
+	return string(data[4 : 4+keyLength]), data[4+keyLength:], true
+}
@@ -0,0 +1,71 @@
+package restclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndLoadCache(t *testing.T) {
+
+	//Start the counts of the calls to the test resources from 0
+	resetFlakyCalls()
+
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	//Create a cached pool and save two responses
+	client := NewClient()
+	client.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	client.Get("http://localhost:8080/counter?id=snapshot1&directives=max-age=60")
+	client.Get("http://localhost:8080/counter?id=snapshot2&directives=max-age=60")
+
+	if err := client.SaveCache("http://localhost:8080/counter.*", path); err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	//Checks that the responses are loaded by a new client, and returned from the cache
+	restarted := NewClient()
+	restarted.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	if loaded, err := restarted.LoadCache("http://localhost:8080/counter.*", path); err != nil || loaded != 2 {
+		t.Fatal("The responses were not loaded", loaded, err)
+	}
+
+	if response, _ := restarted.Get("http://localhost:8080/counter?id=snapshot1&directives=max-age=60"); !response.CachedContent || string(response.Body) != "{\"call\":1}" {
+		t.Fatal("The response should be cached", response)
+	}
+
+	//Checks that a truncated file loads the entries before the damage
+	data, _ := ioutil.ReadFile(path)
+	ioutil.WriteFile(path, data[:len(data)-10], 0644)
+
+	truncated := NewClient()
+	truncated.AddCustomPool("http://localhost:8080/counter.*", &PoolConfig{CacheElements: 10})
+
+	if loaded, err := truncated.LoadCache("http://localhost:8080/counter.*", path); err != nil || loaded != 1 {
+		t.Fatal("The first response was not loaded", loaded, err)
+	}
+
+	//Checks that the files of other formats are rejected
+	ioutil.WriteFile(path, []byte("corrupt"), 0644)
+
+	if _, err := truncated.LoadCache("http://localhost:8080/counter.*", path); err != ErrInvalidSnapshot {
+		t.Fatal("We should had got an invalid snapshot error", err)
+	}
+
+	//Checks that the pools without cache are rejected
+	if err := truncated.SaveCache("http://localhost:8080/other.*", path); err != ErrPoolWithoutCache {
+		t.Fatal("We should had got a pool without cache error", err)
+	}
+
+	if _, err := truncated.LoadCache("http://localhost:8080/counter.*", filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Fatal("We should had got a not exist error", err)
+	}
+
+	//The end
+	fmt.Println("End TestSaveAndLoadCache")
+
+}
@@ -0,0 +1,138 @@
+package restclient
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//StreamResponse holds the information about the response of a streamed call,
+//the Body is read directly from the connection and must be closed by the caller
+type StreamResponse struct {
+	Body    io.ReadCloser
+	Code    int
+	Headers map[string][]string
+}
+
+//GetStream execute a HTTP GET call to the specified url returning the body without reading it
+func GetStream(callURL string, headers ...Header) (*StreamResponse, error) {
+	return defaultClient.GetStream(callURL, headers...)
+}
+
+//PostStream execute a HTTP POST call to the specified url sending the body from the reader
+func PostStream(callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return defaultClient.PostStream(callURL, body, headers...)
+}
+
+//PutStream execute a HTTP PUT call to the specified url sending the body from the reader
+func PutStream(callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return defaultClient.PutStream(callURL, body, headers...)
+}
+
+//DoStream execute a HTTP call with any method streaming the request and response bodies
+func DoStream(method string, callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return defaultClient.DoStream(method, callURL, body, headers...)
+}
+
+//DoStreamCtx execute a streamed HTTP call with any method, the call is aborted when the context is done
+func DoStreamCtx(ctx context.Context, method string, callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return defaultClient.DoStreamCtx(ctx, method, callURL, body, headers...)
+}
+
+//GetStream execute a HTTP GET call using the client pools returning the body without reading it
+func (c *Client) GetStream(callURL string, headers ...Header) (*StreamResponse, error) {
+	return c.performStreamRequest(context.Background(), http.MethodGet, callURL, nil, getHeadersMap(headers))
+}
+
+//PostStream execute a HTTP POST call using the client pools sending the body from the reader
+func (c *Client) PostStream(callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return c.performStreamRequest(context.Background(), http.MethodPost, callURL, body, getHeadersMap(headers))
+}
+
+//PutStream execute a HTTP PUT call using the client pools sending the body from the reader
+func (c *Client) PutStream(callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return c.performStreamRequest(context.Background(), http.MethodPut, callURL, body, getHeadersMap(headers))
+}
+
+//DoStream execute a HTTP call with any method using the client pools streaming the request and response bodies
+func (c *Client) DoStream(method string, callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return c.performStreamRequest(context.Background(), method, callURL, body, getHeadersMap(headers))
+}
+
+//DoStreamCtx execute a streamed HTTP call using the client pools, the call is aborted when the context is done
+func (c *Client) DoStreamCtx(ctx context.Context, method string, callURL string, body io.Reader, headers ...Header) (*StreamResponse, error) {
+	return c.performStreamRequest(ctx, method, callURL, body, getHeadersMap(headers))
+}
+
+//Execute the request without buffering the bodies, the cache of the pool is never used to get the response
+func (c *Client) performStreamRequest(ctx context.Context, method string, callURL string, body io.Reader, headers map[string]string) (*StreamResponse, error) {
+	//Get the rClient for the url
+	rclient := c.getPool(callURL)
+
+	callURL = rclient.getURL(callURL)
+
+	//The mocks can only be compared with calls without body, reading the body would defeat the streaming
+	if c.useMock && body == nil {
+		r := c.searchMockCall(method, callURL, headers, "")
+		if r != nil {
+			return &StreamResponse{ioutil.NopCloser(strings.NewReader(r.Body)), r.Code, r.Headers}, nil
+		}
+	}
+
+	//Pass the rate limit, the bulkhead and the circuit breaker of the pool
+	if error := rclient.acquire(ctx); error != nil {
+		return nil, error
+	}
+
+	streamResponse, error := rclient.sendStream(ctx, method, callURL, body, headers)
+
+	var response *Response
+	if streamResponse != nil {
+		response = &Response{Code: streamResponse.Code, Headers: streamResponse.Headers}
+	}
+
+	rclient.done(response, error)
+
+	//The successful unsafe calls change the resource, so its cached responses are removed
+	rclient.invalidateAfter(method, callURL, response)
+
+	//Keep the slot until the caller closes the body
+	if streamResponse != nil && rclient.bulkhead != nil {
+		streamResponse.Body = &bulkheadBody{ReadCloser: streamResponse.Body, bulkhead: rclient.bulkhead}
+	} else {
+		rclient.release()
+	}
+
+	return streamResponse, error
+}
+
+//Send the request to the API without reading the response body
+func (rclient *rClient) sendStream(ctx context.Context, method string, callURL string, body io.Reader, headers map[string]string) (*StreamResponse, error) {
+	//Create the request to the API
+	request, error := http.NewRequestWithContext(ctx, method, callURL, body)
+
+	//Checks for errors in the connection
+	if error != nil {
+		return nil, error
+	}
+
+	//Set headers
+	setHeaders(request, headers)
+
+	//perform the request through the client
+	response, error := rclient.client.Do(request)
+
+	//The redirects are not followed, so we return the redirect response without body
+	if urlError, ok := error.(*url.Error); ok && urlError.Err == notFollowRedirectError {
+		return &StreamResponse{ioutil.NopCloser(strings.NewReader("")), response.StatusCode, response.Header}, nil
+	}
+
+	if error != nil {
+		return nil, requestError(ctx, rclient, callURL, error)
+	}
+
+	return &StreamResponse{response.Body, response.StatusCode, response.Header}, nil
+}
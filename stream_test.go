@@ -0,0 +1,102 @@
+package restclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPostStream(t *testing.T) {
+
+	//Do the POST call sending the body from a reader
+	response, err := PostStream("http://localhost:8080/echo", strings.NewReader("{\"id\":\"MLA\"}"))
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	defer response.Body.Close()
+
+	//Checks if the response was 200OK
+	if response.Code != 200 {
+		t.Fatal("There was not 200OK", "200", response.Code)
+	}
+
+	//Reads the streamed body
+	body, err := ioutil.ReadAll(response.Body)
+
+	//Checks if the content of the body is as expected
+	if err != nil || string(body) != "POST --> {\"id\":\"MLA\"}" {
+		t.Fatal("The content was not as expected", "POST --> {\"id\":\"MLA\"}", string(body), err)
+	}
+
+	//The end
+	fmt.Println("End TestPostStream")
+
+}
+
+func TestGetStreamWithoutCache(t *testing.T) {
+
+	//Create a client with a cached pool
+	client := NewClient()
+
+	config := new(PoolConfig)
+	config.CacheElements = 100
+
+	client.AddCustomPool("http://localhost:8080", config)
+
+	//Do the GET call of a cacheable resource
+	response, err := client.GetStream("http://localhost:8080/cache?seconds=10")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+
+	//Checks if the content of the body is as expected
+	if string(body) != "{\"id\":\"MLA\"}" {
+		t.Fatal("The content was not as expected", "{\"id\":\"MLA\"}", string(body))
+	}
+
+	//Checks that the streamed response was not saved in the cache
+	if client.getPool("http://localhost:8080").getElement("http://localhost:8080/cache?seconds=10") != nil {
+		t.Fatal("The streamed response should not be cached")
+	}
+
+	//The end
+	fmt.Println("End TestGetStreamWithoutCache")
+
+}
+
+func TestGetStreamWithMock(t *testing.T) {
+
+	//Create a client with a mock
+	client := NewClient()
+	client.AddMock("http://fer.com/export", http.MethodGet, "", Response{Body: "{\"id\":\"FER\"}", Code: 200})
+
+	//Do the GET call
+	response, err := client.GetStream("http://fer.com/export")
+
+	//Checks if there was an error
+	if err != nil {
+		t.Fatal("We got an error", err)
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+
+	//Checks if the content of the body is the mocked one
+	if string(body) != "{\"id\":\"FER\"}" {
+		t.Fatal("The content was not as expected", "{\"id\":\"FER\"}", string(body))
+	}
+
+	//The end
+	fmt.Println("End TestGetStreamWithMock")
+
+}